@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
+	"github.com/spf13/cobra"
+	"io"
+)
+
+var configShowResolve bool
+
+func init() {
+	addConfigFlags(configShowCmd)
+	configShowCmd.Flags().BoolVar(&configShowResolve, "resolve", false, "check the configuration and resolve the version and the execute files of the terraform provider over the network")
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configClearCacheCmd)
+	configCmd.AddCommand(configSchemaCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration init and generate run with",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+// Takes the same flags as init and generate, so it shows exactly what they would run with. It does not touch the
+// network or the local cache unless it is asked to resolve the configuration.
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration, the flags, env variables, configuration file and cache merged",
+	Long:  ``,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		options, err := newConfigOptions(cmd, true)
+		if err != nil {
+			return err
+		}
+		config, err := generate_selefra_terraform_provider.NewMergedConfigFromOptions(options, configShowResolve)
+		if err != nil {
+			return fmt.Errorf("create config failed: %w", err)
+		}
+		configBytes, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(configBytes))
+		return err
+	},
+}
+
+var configClearCacheCmd = &cobra.Command{
+	Use:   "clear-cache",
+	Short: "Remove the configuration cached by the previous run, the next run builds it again",
+	Long:  ``,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cleared, err := generate_selefra_terraform_provider.ClearLocalCache()
+		if err != nil {
+			return fmt.Errorf("clear config cache failed: %w", err)
+		}
+		result := &struct {
+			Path    string `json:"path"`
+			Cleared bool   `json:"cleared"`
+		}{
+			Path:    generate_selefra_terraform_provider.GetLocalCachePath(),
+			Cleared: cleared,
+		}
+		return printResult(cmd, result, func(writer io.Writer) error {
+			if result.Cleared {
+				_, err = fmt.Fprintf(writer, "config cache %s removed\n", result.Path)
+			} else {
+				_, err = fmt.Fprintf(writer, "there is no config cache %s\n", result.Path)
+			}
+			return err
+		})
+	},
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the configuration file, for editors and CI to validate config.yml against",
+	Long:  ``,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), generate_selefra_terraform_provider.ConfigJsonSchema)
+		return err
+	},
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"strings"
+)
+
+// The flags shared by init and generate, each one can also be set through the environment variables it is bound to,
+// the flag wins over the environment variable, which wins over the configuration file, which wins over the local cache
+func addConfigFlags(command *cobra.Command) {
+	flags := command.Flags()
+	flags.String("config", "", "path of the configuration file, env "+generate_selefra_terraform_provider.EnvConfigPath)
+	flags.String("provider-url", "", "repository URL of the terraform provider, env "+generate_selefra_terraform_provider.EnvTerraformProviderUrl)
+	flags.String("module", "", "go module name of the generated selefra provider, env "+generate_selefra_terraform_provider.EnvModuleName)
+	flags.String("output-dir", "", "directory the selefra provider is generated into, env "+generate_selefra_terraform_provider.EnvOutputDirectory)
+	flags.StringSlice("resources", nil, "comma separated terraform resources to generate, all if not set, env "+generate_selefra_terraform_provider.EnvTerraformProviderResources)
+	flags.String("version", "", "version of the terraform provider, the latest release if not set, env "+generate_selefra_terraform_provider.EnvTerraformProviderVersion)
+	flags.String("provider-config", "", "configuration the terraform provider is started with as a JSON object, env "+generate_selefra_terraform_provider.EnvTerraformProviderConfig)
+	flags.StringSlice("git-hosts", nil, "comma separated git hosts besides the public ones the module name may be detected from, env "+generate_selefra_terraform_provider.EnvGitHosts)
+	flags.StringSlice("platforms", nil, "comma separated os/arch the generated provider has files for, all if not set, env "+generate_selefra_terraform_provider.EnvTerraformProviderPlatforms)
+	flags.String("templates", "", "directory of templates that override the embedded ones, same as output.templates-dir")
+}
+
+// Resolve the options of the command from its flags and the environment variables they are bound to
+func newConfigOptions(command *cobra.Command, useLocalCache bool) (*generate_selefra_terraform_provider.ConfigOptions, error) {
+	v := viper.New()
+	bindings := map[string][]string{
+		"config":          {generate_selefra_terraform_provider.EnvConfigPath},
+		"provider-url":    {generate_selefra_terraform_provider.EnvTerraformProviderUrl, generate_selefra_terraform_provider.EnvTerraformProvider},
+		"module":          {generate_selefra_terraform_provider.EnvModuleName},
+		"output-dir":      {generate_selefra_terraform_provider.EnvOutputDirectory},
+		"resources":       {generate_selefra_terraform_provider.EnvTerraformProviderResources},
+		"version":         {generate_selefra_terraform_provider.EnvTerraformProviderVersion},
+		"provider-config": {generate_selefra_terraform_provider.EnvTerraformProviderConfig},
+		"git-hosts":       {generate_selefra_terraform_provider.EnvGitHosts},
+		"platforms":       {generate_selefra_terraform_provider.EnvTerraformProviderPlatforms},
+		"templates":       nil,
+	}
+	for key, envSlice := range bindings {
+		if err := v.BindPFlag(key, command.Flags().Lookup(key)); err != nil {
+			return nil, err
+		}
+		if len(envSlice) == 0 {
+			continue
+		}
+		if err := v.BindEnv(append([]string{key}, envSlice...)...); err != nil {
+			return nil, err
+		}
+	}
+
+	return &generate_selefra_terraform_provider.ConfigOptions{
+		ConfigPath:           v.GetString("config"),
+		TerraformProviderUrl: v.GetString("provider-url"),
+		ModuleName:           v.GetString("module"),
+		OutputDirectory:      v.GetString("output-dir"),
+		// The environment variable is not split on commas by viper
+		Resources:                generate_selefra_terraform_provider.SplitResources(strings.Join(v.GetStringSlice("resources"), ",")),
+		TerraformProviderVersion: v.GetString("version"),
+		TerraformProviderConfig:  v.GetString("provider-config"),
+		GitHosts:                 generate_selefra_terraform_provider.SplitResources(strings.Join(v.GetStringSlice("git-hosts"), ",")),
+		Platforms:                generate_selefra_terraform_provider.SplitResources(strings.Join(v.GetStringSlice("platforms"), ",")),
+		TemplatesDirectory:       v.GetString("templates"),
+		UseLocalCache:            useLocalCache,
+	}, nil
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addConfigFlags(doctor)
+	rootCmd.AddCommand(doctor)
+}
+
+var doctor = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment and the project before running init or generate",
+	Long: `Check the go toolchain, the module name detection, the configuration, whether the terraform provider has an
+execute file for this platform, whether GitHub and the other remotes the provider is resolved from are reachable, and
+whether the go files in the provider directory parse. Each check prints pass, warn or fail with a hint, the command
+fails when any check fails.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		options, err := newConfigOptions(cmd, false)
+		if err != nil {
+			return err
+		}
+		report := generate_selefra_terraform_provider.NewDoctor(options).Run(context.Background())
+		if err := printResult(cmd, report, report.WriteText); err != nil {
+			return err
+		}
+		return report.Err()
+	},
+}
@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
 	"github.com/spf13/cobra"
-	"github.com/yezihack/colorlog"
+	"io"
+	"time"
 )
 
+var generateDryRun bool
+
 func init() {
+	addConfigFlags(generate)
+	generate.Flags().BoolVar(&generateDryRun, "dry-run", false, "do not write the project, print a unified diff of what generate would change instead")
 	rootCmd.AddCommand(generate)
 }
 
@@ -14,26 +20,48 @@ var generate = &cobra.Command{
 	Use:   "generate",
 	Short: "Generate selefra provider from terraform's provider",
 	Long:  ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		colorlog.Info("begin run generate...")
+		startTime := time.Now()
+		logger := generate_selefra_terraform_provider.GetDefaultLogger()
+		logger.Info("begin run generate...")
 
-		var config *generate_selefra_terraform_provider.Config
-		var err error
-		config, err = generate_selefra_terraform_provider.NewConfigFromLocalJson()
+		options, err := newConfigOptions(cmd, true)
 		if err != nil {
-			config, err = generate_selefra_terraform_provider.NewConfigFromEnv()
+			return err
+		}
+		// Set before the config is built, so that the config cached locally is not written either
+		var memoryFileSystem *generate_selefra_terraform_provider.MemoryFileSystem
+		if generateDryRun {
+			memoryFileSystem = generate_selefra_terraform_provider.NewMemoryFileSystem(generate_selefra_terraform_provider.NewOsFileSystem())
+			options.FileSystem = memoryFileSystem
 		}
+		config, err := generate_selefra_terraform_provider.NewConfigFromOptions(options)
 		if err != nil {
-			return
+			return fmt.Errorf("create config failed: %w", err)
+		}
+
+		recordingFileSystem := generate_selefra_terraform_provider.NewRecordingFileSystem(config.GetFileSystem())
+		config.SetFileSystem(recordingFileSystem)
+
+		generator := generate_selefra_terraform_provider.NewGenerator(config)
+		if err := generator.Run(); err != nil {
+			return fmt.Errorf("run generate failed: %w", err)
 		}
 
-		err = generate_selefra_terraform_provider.NewGenerator(config).Run()
+		result := newProjectResult("generate", config, generator.GetSummary(), recordingFileSystem, startTime)
+		result.DryRun = generateDryRun
+		err = printResult(cmd, result, func(writer io.Writer) error {
+			if memoryFileSystem != nil {
+				return memoryFileSystem.WriteDryRunReport(writer)
+			}
+			return result.printText(writer)
+		})
 		if err != nil {
-			colorlog.Error("run generate failed: %s", err.Error())
-		} else {
-			colorlog.Info("run generate done")
+			return fmt.Errorf("print result failed: %w", err)
 		}
+		logger.Info("run generate done")
+		return nil
 
 	},
 }
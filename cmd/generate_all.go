@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
+	"github.com/spf13/cobra"
+)
+
+var (
+	generateAllManifestPath  string
+	generateAllConcurrency   int
+	generateAllDownloadCache string
+)
+
+func init() {
+	flags := generateAll.Flags()
+	flags.StringVar(&generateAllManifestPath, "manifest", "providers.yaml", "path of the manifest that lists the providers to generate")
+	flags.IntVar(&generateAllConcurrency, "concurrency", 0, "how many providers are generated at the same time, overrides the concurrency of the manifest")
+	flags.StringVar(&generateAllDownloadCache, "download-cache", "", "directory the providers share their downloads in, overrides the download-cache of the manifest")
+	rootCmd.AddCommand(generateAll)
+}
+
+var generateAll = &cobra.Command{
+	Use:   "generate-all",
+	Short: "Generate every selefra provider listed in a manifest",
+	Long:  ``,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		logger := generate_selefra_terraform_provider.GetDefaultLogger()
+		logger.Info("begin run generate-all...")
+
+		manifest, err := generate_selefra_terraform_provider.NewManifestFromPath(generateAllManifestPath)
+		if err != nil {
+			return fmt.Errorf("read manifest failed: %w", err)
+		}
+		if generateAllConcurrency > 0 {
+			manifest.Concurrency = generateAllConcurrency
+		}
+		if generateAllDownloadCache != "" {
+			manifest.DownloadCache = generateAllDownloadCache
+		}
+
+		report := generate_selefra_terraform_provider.NewManifestRunner(manifest).SetLogger(logger).Run(context.Background())
+		if err := printResult(cmd, report, report.WriteTable); err != nil {
+			return err
+		}
+		if err := report.Err(); err != nil {
+			return fmt.Errorf("run generate-all failed: %w", err)
+		}
+		logger.Info("run generate-all done")
+		return nil
+	},
+}
@@ -2,12 +2,18 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
 	"github.com/spf13/cobra"
-	"github.com/yezihack/colorlog"
+	"io"
+	"time"
 )
 
+var initDryRun bool
+
 func init() {
+	addConfigFlags(initSelefraTerraformProvider)
+	initSelefraTerraformProvider.Flags().BoolVar(&initDryRun, "dry-run", false, "do not write the project, print a unified diff of what init would change instead")
 	rootCmd.AddCommand(initSelefraTerraformProvider)
 }
 
@@ -16,22 +22,47 @@ var initSelefraTerraformProvider = &cobra.Command{
 	Use:   "init",
 	Short: "init selefra + terraform provider project",
 	Long:  ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		colorlog.Info("begin exec init...")
+		startTime := time.Now()
+		logger := generate_selefra_terraform_provider.GetDefaultLogger()
+		logger.Info("begin exec init...")
 
-		config, err := generate_selefra_terraform_provider.NewConfigFromEnv()
+		options, err := newConfigOptions(cmd, true)
+		if err != nil {
+			return err
+		}
+		// Set before the config is built, so that the config cached locally is not written either
+		var memoryFileSystem *generate_selefra_terraform_provider.MemoryFileSystem
+		if initDryRun {
+			memoryFileSystem = generate_selefra_terraform_provider.NewMemoryFileSystem(generate_selefra_terraform_provider.NewOsFileSystem())
+			options.FileSystem = memoryFileSystem
+		}
+		config, err := generate_selefra_terraform_provider.NewConfigFromOptions(options)
 		if err != nil {
-			colorlog.Error("create config failed: %s, init failed, exit", err.Error())
-			return
+			return fmt.Errorf("create config failed: %w", err)
+		}
+		recordingFileSystem := generate_selefra_terraform_provider.NewRecordingFileSystem(config.GetFileSystem())
+		config.SetFileSystem(recordingFileSystem)
+
+		selefraTerraformProviderInit := generate_selefra_terraform_provider.NewSelefraTerraformProviderInit(config)
+		if err := selefraTerraformProviderInit.Run(context.Background()); err != nil {
+			return fmt.Errorf("exec init failed: %w", err)
 		}
 
-		err = generate_selefra_terraform_provider.NewSelefraTerraformProviderInit(config).Run(context.Background())
+		result := newProjectResult("init", config, selefraTerraformProviderInit.GetSummary(), recordingFileSystem, startTime)
+		result.DryRun = initDryRun
+		err = printResult(cmd, result, func(writer io.Writer) error {
+			if memoryFileSystem != nil {
+				return memoryFileSystem.WriteDryRunReport(writer)
+			}
+			return result.printText(writer)
+		})
 		if err != nil {
-			colorlog.Error("exec init failed: %s, exit", err.Error())
-		} else {
-			colorlog.Info("exec init done")
+			return fmt.Errorf("print result failed: %w", err)
 		}
+		logger.Info("exec init done")
+		return nil
 
 	},
 }
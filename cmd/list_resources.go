@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
+	"github.com/spf13/cobra"
+	"io"
+)
+
+var listResourcesOptions generate_selefra_terraform_provider.ResourceListOptions
+
+func init() {
+	addConfigFlags(listResources)
+	flags := listResources.Flags()
+	flags.StringSliceVar(&listResourcesOptions.Patterns, "filter", nil, "comma separated glob patterns like aws_s3_*, only the matching names are listed")
+	flags.StringVar(&listResourcesOptions.Kind, "kind", "", "list only the resource or the data_source entries, both if not set")
+	flags.StringVar(&listResourcesOptions.Search, "search", "", "list only the entries whose name, description or deprecation message contains the text, case-insensitively")
+	rootCmd.AddCommand(listResources)
+}
+
+var listResources = &cobra.Command{
+	Use:   "list-resources",
+	Short: "List the resources and data sources of the terraform provider, to decide what to put in terraform.provider.resources",
+	Long: `List the resources and data sources of the terraform provider with their column count, whether they have an id
+and so whether a table would be generated for them, their nested block count and their deprecation message.
+schema.json is used when it is there, otherwise the provider is started once and schema.json is written.`,
+	Annotations: map[string]string{annotationCsvOutput: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		logger := generate_selefra_terraform_provider.GetDefaultLogger()
+		logger.Info("begin list resources...")
+
+		options, err := newConfigOptions(cmd, true)
+		if err != nil {
+			return err
+		}
+		config, err := generate_selefra_terraform_provider.NewConfigFromOptions(options)
+		if err != nil {
+			return fmt.Errorf("create config failed: %w", err)
+		}
+
+		entries, err := generate_selefra_terraform_provider.NewSchemaIRManager(config).ListResources(context.Background(), &listResourcesOptions)
+		if err != nil {
+			return fmt.Errorf("list resources failed: %w", err)
+		}
+
+		err = printResult(cmd, entries, func(writer io.Writer) error {
+			if isCsvOutput() {
+				return generate_selefra_terraform_provider.WriteResourceListCsv(writer, entries)
+			}
+			return generate_selefra_terraform_provider.WriteResourceListTable(writer, entries)
+		})
+		if err != nil {
+			return fmt.Errorf("print result failed: %w", err)
+		}
+		logger.Info("list resources done, %d listed", len(entries))
+		return nil
+	},
+}
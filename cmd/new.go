@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+var (
+	newProjectOptions   generate_selefra_terraform_provider.NewProjectOptions
+	newProjectAssumeYes bool
+)
+
+func init() {
+	flags := newProjectCmd.Flags()
+	flags.StringVar(&newProjectOptions.SearchQuery, "search", "", "search the terraform registry for the provider instead of asking what to search for")
+	flags.StringVar(&newProjectOptions.TerraformProviderUrl, "provider-url", "", "repository URL of the terraform provider, the registry is not searched when it is set")
+	flags.StringVar(&newProjectOptions.TerraformProviderVersion, "version", "", "version of the terraform provider, the latest one if not set")
+	flags.StringVar(&newProjectOptions.ModuleName, "module", "", "go module name of the new selefra provider")
+	flags.StringVar(&newProjectOptions.OutputDirectory, "output-dir", "", "directory the project is created in, ./selefra-provider-<name> if not set")
+	flags.StringSliceVar(&newProjectOptions.Resources, "resources", nil, "comma separated resources to generate tables for, numbers, ranges and patterns like aws_s3_* work too")
+	flags.StringVar(&newProjectOptions.RegistryUrl, "registry-url", generate_selefra_terraform_provider.DefaultTerraformRegistryUrl, "the terraform registry to search")
+	flags.StringVar(&newProjectOptions.Network.Proxy, "proxy", "", "the proxy the registry and the provider releases are reached through, HTTPS_PROXY if not set")
+	flags.StringSliceVar(&newProjectOptions.Network.NoProxy, "no-proxy", nil, "comma separated hosts that are reached without the proxy")
+	flags.StringVar(&newProjectOptions.Network.CaFile, "ca-file", "", "a PEM file of the certificates to trust besides the ones of the system")
+	flags.StringVar(&newProjectOptions.Network.Timeout, "timeout", "", "how long a request may take, a go duration like 90s")
+	flags.IntVar(&newProjectOptions.Network.Retries, "retries", 0, "how many times a failed request is retried, a negative number to never retry")
+	flags.BoolVarP(&newProjectAssumeYes, "yes", "y", false, "do not ask, answer every question with its default")
+	rootCmd.AddCommand(newProjectCmd)
+}
+
+var newProjectCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Create a selefra provider project from scratch, asking for whatever the flags do not answer",
+	Long:  ``,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		startTime := time.Now()
+		logger := generate_selefra_terraform_provider.GetDefaultLogger()
+		logger.Info("begin exec new...")
+
+		// With --output json stdout only carries the result, so the questions are asked on stderr
+		promptWriter := cmd.OutOrStdout()
+		if isJsonOutput() {
+			promptWriter = cmd.ErrOrStderr()
+		}
+		prompter := generate_selefra_terraform_provider.NewPrompter(cmd.InOrStdin(), promptWriter, newProjectAssumeYes)
+		recordingFileSystem := generate_selefra_terraform_provider.NewRecordingFileSystem(generate_selefra_terraform_provider.NewOsFileSystem())
+		newProjectOptions.FileSystem = recordingFileSystem
+		wizard := generate_selefra_terraform_provider.NewProjectWizard(&newProjectOptions, prompter)
+		config, err := wizard.Run(context.Background())
+		if err != nil {
+			return fmt.Errorf("exec new failed: %w", err)
+		}
+
+		result := newProjectResult("new", config, wizard.GetSummary(), recordingFileSystem, startTime)
+		if err := printResult(cmd, result, result.printText); err != nil {
+			return fmt.Errorf("print result failed: %w", err)
+		}
+		logger.Info("exec new done")
+		return nil
+	},
+}
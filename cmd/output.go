@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
+	"github.com/spf13/cobra"
+	"io"
+	"time"
+)
+
+const (
+	outputFormatText = "text"
+	outputFormatJson = "json"
+	outputFormatCsv  = "csv"
+)
+
+// The commands that can print their result as CSV have this annotation set to "true"
+const annotationCsvOutput = "csv-output"
+
+var (
+	outputFormat string
+	logFormat    string
+	logLevel     string
+)
+
+// The logs always go to stderr, so with --output json stdout only carries the result
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.StringVarP(&outputFormat, "output", "o", outputFormatText, "format of the command result, text or json, list-resources also takes csv")
+	flags.StringVar(&logFormat, "log-format", generate_selefra_terraform_provider.LogFormatText, "format of the logs written to stderr, text or json")
+	flags.StringVar(&logLevel, "log-level", generate_selefra_terraform_provider.LogLevelInfo, "the least important logs that are written, debug, info, warn or error")
+}
+
+// Set up the default logger from the flags, everything that is not given a logger uses it
+func initLogger(cmd *cobra.Command) error {
+	switch {
+	case outputFormat == outputFormatText || outputFormat == outputFormatJson:
+	case outputFormat == outputFormatCsv && isCsvOutputSupported(cmd):
+	case isCsvOutputSupported(cmd):
+		return fmt.Errorf("%w: unknown output format %s, it must be %s, %s or %s", generate_selefra_terraform_provider.ErrCheckConfigFailed, outputFormat, outputFormatText, outputFormatJson, outputFormatCsv)
+	default:
+		return fmt.Errorf("%w: unknown output format %s, it must be %s or %s", generate_selefra_terraform_provider.ErrCheckConfigFailed, outputFormat, outputFormatText, outputFormatJson)
+	}
+	logger, err := generate_selefra_terraform_provider.NewLogger(cmd.ErrOrStderr(), logFormat, logLevel)
+	if err != nil {
+		return fmt.Errorf("%w: %s", generate_selefra_terraform_provider.ErrCheckConfigFailed, err.Error())
+	}
+	generate_selefra_terraform_provider.SetDefaultLogger(logger)
+	return nil
+}
+
+func isJsonOutput() bool {
+	return outputFormat == outputFormatJson
+}
+
+func isCsvOutput() bool {
+	return outputFormat == outputFormatCsv
+}
+
+func isCsvOutputSupported(cmd *cobra.Command) bool {
+	return cmd.Annotations[annotationCsvOutput] == "true"
+}
+
+// Print the result of a command as JSON with --output json, otherwise through printText
+func printResult(cmd *cobra.Command, result any, printText func(writer io.Writer) error) error {
+	if isJsonOutput() {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	if printText == nil {
+		return nil
+	}
+	return printText(cmd.OutOrStdout())
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// The result of init, generate and new
+type projectResult struct {
+	Command         string                                                 `json:"command"`
+	ModuleName      string                                                 `json:"module_name"`
+	OutputDirectory string                                                 `json:"output_directory"`
+	DryRun          bool                                                   `json:"dry_run"`
+	Resources       int                                                    `json:"resources"`
+	Tables          int                                                    `json:"tables"`
+	Skipped         []*generate_selefra_terraform_provider.SkippedResource `json:"skipped"`
+	WrittenFiles    []string                                               `json:"written_files"`
+	DurationSeconds float64                                                `json:"duration_seconds"`
+}
+
+func newProjectResult(command string, config *generate_selefra_terraform_provider.Config, summary *generate_selefra_terraform_provider.GenerateSummary,
+	recordingFileSystem *generate_selefra_terraform_provider.RecordingFileSystem, startTime time.Time) *projectResult {
+	result := &projectResult{
+		Command:         command,
+		ModuleName:      config.Selefra.ModuleName,
+		OutputDirectory: config.Output.Directory,
+		Skipped:         make([]*generate_selefra_terraform_provider.SkippedResource, 0),
+		WrittenFiles:    recordingFileSystem.WrittenFiles(),
+		DurationSeconds: time.Since(startTime).Seconds(),
+	}
+	if summary != nil {
+		result.Resources = summary.Resources
+		result.Tables = summary.Tables
+		result.Skipped = append(result.Skipped, summary.Skipped...)
+	}
+	return result
+}
+
+// The text result is short, the logs already told what happened
+func (x *projectResult) printText(writer io.Writer) error {
+	_, err := fmt.Fprintf(writer, "%s done in %.1fs: %d resources, %d tables, %d skipped, %d files written to %s\n",
+		x.Command, x.DurationSeconds, x.Resources, x.Tables, len(x.Skipped), len(x.WrittenFiles), x.OutputDirectory)
+	return err
+}
+
+// The error of a failed command with --output json
+type errorResult struct {
+	Error    string `json:"error"`
+	ExitCode int    `json:"exit_code"`
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/fatih/color"
 	cc "github.com/ivanpirog/coloredcobra"
+	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
 	"github.com/spf13/cobra"
 	"os"
 )
@@ -12,6 +13,13 @@ var rootCmd = &cobra.Command{
 	Use:   "",
 	Short: "",
 	Long:  ``,
+	// The error is printed once by Execute
+	SilenceErrors: true,
+	// The flags were parsed fine when a command fails, so the usage would only hide the error
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return initLogger(cmd)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		_ = cmd.Help()
 	},
@@ -29,8 +37,14 @@ func Execute() {
 	})
 	rootCmd.SetOut(color.Output)
 
+	// The exit code tells the kind of the failure, see generate_selefra_terraform_provider.ExitCode
 	if err := rootCmd.Execute(); err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		exitCode := generate_selefra_terraform_provider.ExitCode(err)
+		if isJsonOutput() {
+			_ = printResult(rootCmd, &errorResult{Error: err.Error(), ExitCode: exitCode}, nil)
+		} else {
+			_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		os.Exit(exitCode)
 	}
 }
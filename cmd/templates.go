@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/selefra/selefra-terraform-provider-scaffolding/generate_selefra_terraform_provider"
+	"github.com/spf13/cobra"
+)
+
+var templatesExportDirectory string
+var templatesExportForce bool
+var templatesCheckDirectory string
+
+func init() {
+	templatesExport.Flags().StringVarP(&templatesExportDirectory, "dir", "d", "./templates", "directory to write the embedded templates to")
+	templatesExport.Flags().BoolVar(&templatesExportForce, "force", false, "overwrite templates that already exist in the directory")
+	templatesCheck.Flags().StringVarP(&templatesCheckDirectory, "dir", "d", "./templates", "directory of the templates to check")
+	templates.AddCommand(templatesExport)
+	templates.AddCommand(templatesCheck)
+	rootCmd.AddCommand(templates)
+}
+
+// The templates used to render the project can be overridden by putting a file with the same name into output.templates-dir
+var templates = &cobra.Command{
+	Use:   "templates",
+	Short: "Manage the templates used to render the selefra provider",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+var templatesExport = &cobra.Command{
+	Use:   "export",
+	Short: "Export the embedded templates so that they can be edited and used as overrides",
+	Long:  ``,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := generate_selefra_terraform_provider.GetDefaultLogger()
+		exportPathSlice, err := generate_selefra_terraform_provider.ExportTemplates(templatesExportDirectory, templatesExportForce)
+		for _, exportPath := range exportPathSlice {
+			logger.Info("export template %s", exportPath)
+		}
+		if err != nil {
+			return fmt.Errorf("export templates failed: %w", err)
+		}
+		logger.Info("export templates done, set output.templates-dir to %s to use them", templatesExportDirectory)
+		result := &struct {
+			Directory    string   `json:"directory"`
+			WrittenFiles []string `json:"written_files"`
+		}{
+			Directory:    templatesExportDirectory,
+			WrittenFiles: exportPathSlice,
+		}
+		return printResult(cmd, result, nil)
+	},
+}
+
+var templatesCheck = &cobra.Command{
+	Use:   "check",
+	Short: "Check that the overridden templates only reference fields that exist on the render params",
+	Long:  ``,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generate_selefra_terraform_provider.CheckTemplatesDirectory(templatesCheckDirectory); err != nil {
+			return fmt.Errorf("check templates failed: %w", err)
+		}
+		generate_selefra_terraform_provider.GetDefaultLogger().Info("check templates done, all templates in %s are ok", templatesCheckDirectory)
+		result := &struct {
+			Directory string `json:"directory"`
+			Ok        bool   `json:"ok"`
+		}{
+			Directory: templatesCheckDirectory,
+			Ok:        true,
+		}
+		return printResult(cmd, result, nil)
+	},
+}
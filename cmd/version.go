@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"io"
 )
 
 const Version = "v0.0.1"
@@ -15,7 +16,15 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show the version number of selefra terraform provider scaffolding",
 	Long:  ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		color.HiGreen("version %s", Version)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result := &struct {
+			Version string `json:"version"`
+		}{
+			Version: Version,
+		}
+		return printResult(cmd, result, func(writer io.Writer) error {
+			color.HiGreen("version %s", Version)
+			return nil
+		})
 	},
 }
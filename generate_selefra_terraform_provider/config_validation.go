@@ -0,0 +1,275 @@
+package generate_selefra_terraform_provider
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// ConfigJsonSchema The JSON Schema of config.yml, editors that understand the yaml-language-server modeline use it for
+// completion and validation, the config schema command prints it
+//
+//go:embed config.schema.json
+var ConfigJsonSchema string
+
+// ConfigProblem One thing that is wrong with the configuration
+type ConfigProblem struct {
+
+	// Where in the configuration file the problem is, for example config.yml:12:5, empty if it is not in the file
+	Location string
+
+	// The dotted path of the key, for example terraform.provider.execute-files[0].os
+	Key string
+
+	Message string
+}
+
+func (x *ConfigProblem) String() string {
+	buff := strings.Builder{}
+	if x.Location != "" {
+		buff.WriteString(x.Location + ": ")
+	}
+	if x.Key != "" {
+		buff.WriteString(x.Key + ": ")
+	}
+	buff.WriteString(x.Message)
+	return buff.String()
+}
+
+// ConfigValidationError All the problems found in the configuration, so that they can be fixed in one go,
+// errors.Is(err, ErrCheckConfigFailed) holds for it
+type ConfigValidationError struct {
+	Problems []*ConfigProblem
+}
+
+var _ error = &ConfigValidationError{}
+
+func (x *ConfigValidationError) Error() string {
+	lines := make([]string, 0, len(x.Problems))
+	for _, problem := range x.Problems {
+		lines = append(lines, problem.String())
+	}
+	return fmt.Sprintf("config is invalid, %d problem(s):\n\t%s", len(x.Problems), strings.Join(lines, "\n\t"))
+}
+
+func (x *ConfigValidationError) Unwrap() error {
+	return ErrCheckConfigFailed
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// Find the keys of the configuration file that do not map to any field of the Config, viper would silently drop them
+func checkConfigKeys(configFilePath string, content []byte) ([]*ConfigProblem, error) {
+	return checkYamlKeys(configFilePath, content, reflect.TypeOf(Config{}))
+}
+
+// Find the keys of a yaml document that do not map to any field of the given struct type
+func checkYamlKeys(configFilePath string, content []byte, t reflect.Type) ([]*ConfigProblem, error) {
+	document := &yaml.Node{}
+	if err := yaml.Unmarshal(content, document); err != nil {
+		return nil, err
+	}
+	problems := make([]*ConfigProblem, 0)
+	for _, node := range document.Content {
+		walkConfigNode(configFilePath, node, t, "", &problems)
+	}
+	return problems, nil
+}
+
+func walkConfigNode(configFilePath string, node *yaml.Node, t reflect.Type, keyPath string, problems *[]*ConfigProblem) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fieldMap := configFieldMap(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			childKeyPath := joinConfigKey(keyPath, keyNode.Value)
+			field, exists := fieldMap[strings.ToLower(keyNode.Value)]
+			if !exists {
+				message := "unknown key"
+				if suggestion := suggestConfigKey(keyNode.Value, fieldMap); suggestion != "" {
+					message += ", did you mean " + suggestion + "?"
+				}
+				*problems = append(*problems, &ConfigProblem{
+					Location: fmt.Sprintf("%s:%d:%d", configFilePath, keyNode.Line, keyNode.Column),
+					Key:      childKeyPath,
+					Message:  message,
+				})
+				continue
+			}
+			walkConfigNode(configFilePath, valueNode, field.Type, childKeyPath, problems)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for index, item := range node.Content {
+			walkConfigNode(configFilePath, item, t.Elem(), fmt.Sprintf("%s[%d]", keyPath, index), problems)
+		}
+	}
+}
+
+// The fields of the struct by the lower case key they are decoded from, the same way mapstructure matches them
+func configFieldMap(t reflect.Type) map[string]reflect.StructField {
+	fieldMap := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tagSplit := strings.Split(field.Tag.Get("mapstructure"), ",")
+		name := tagSplit[0]
+		if name == "-" {
+			continue
+		}
+		// The fields of a squashed struct are decoded from the same level
+		if len(tagSplit) > 1 && tagSplit[1] == "squash" {
+			for key, squashedField := range configFieldMap(field.Type) {
+				fieldMap[key] = squashedField
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fieldMap[strings.ToLower(name)] = field
+	}
+	return fieldMap
+}
+
+func joinConfigKey(keyPath, key string) string {
+	if keyPath == "" {
+		return key
+	}
+	return keyPath + "." + key
+}
+
+// The known key closest to the typo, execute-file -> execute-files, if there is one that is close enough
+func suggestConfigKey(key string, fieldMap map[string]reflect.StructField) string {
+	suggestion := ""
+	bestDistance := 3
+	for name := range fieldMap {
+		if distance := editDistance(strings.ToLower(key), name); distance < bestDistance || (distance == bestDistance && name < suggestion) {
+			suggestion, bestDistance = name, distance
+		}
+	}
+	return suggestion
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func minInt(first int, others ...int) int {
+	for _, other := range others {
+		if other < first {
+			first = other
+		}
+	}
+	return first
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// What validateConfig leaves out
+type configValidationOptions struct {
+
+	// For a caller that checks the module name on its own, so that it is neither reported twice nor detected again
+	skipModuleName bool
+}
+
+// Everything that can be checked without the network, the problems of the configuration file's keys come first
+func validateConfig(config *Config, options configValidationOptions) []*ConfigProblem {
+	problems := append(make([]*ConfigProblem, 0), config.keyProblems...)
+	addProblem := func(key, format string, args ...any) {
+		problems = append(problems, &ConfigProblem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	terraformProvider := &config.Terraform.TerraformProvider
+	if terraformProvider.RepoUrl == "" {
+		addProblem("terraform.provider.repo-url", `The Terraform Provider URL is empty. You can solve the problem in the following centralized manner:
+		- If you specify the configuration file, you can specify the repository address of the Terraform Provider to be accessed in the terraform.provider.repo-url of the configuration file
+		- Or you can specify the flag --provider-url or the environment variable TERRAFORM_PROVIDER_URL or TERRAFORM_PROVIDER`)
+	} else if _, err := url.Parse(terraformProvider.RepoUrl); err != nil {
+		addProblem("terraform.provider.repo-url", "%s is not a valid url: %s", terraformProvider.RepoUrl, err.Error())
+	} else if terraformProvider.GetOrParseProviderName() == "" {
+		addProblem("terraform.provider.repo-url", "The Provider name cannot be resolved from the given Terraform Provider URL: %s", terraformProvider.RepoUrl)
+	}
+
+	if terraformProvider.Source != "" {
+		if _, ok := defaultProviderSourceRegistry.Get(terraformProvider.Source); !ok {
+			addProblem("terraform.provider.source", "Unknown source %s, it must be one of %s", terraformProvider.Source, strings.Join(defaultProviderSourceRegistry.Names(), ", "))
+		}
+	}
+
+	if _, err := compileProviderAssetPattern(terraformProvider.AssetPattern); err != nil {
+		addProblem("terraform.provider.asset-pattern", "%s", err.Error())
+	}
+	for index, platform := range terraformProvider.Platforms {
+		if _, _, err := parsePlatform(platform); err != nil {
+			addProblem(fmt.Sprintf("terraform.provider.platforms[%d]", index), "%s", err.Error())
+		}
+	}
+
+	// Otherwise it is only found once the provider is started, the references are not resolved here so that no secret
+	// is needed to check the configuration
+	for _, problem := range terraformProvider.checkProviderConfigReferences() {
+		addProblem("terraform.provider.config", "%s", problem)
+	}
+
+	// If the module name is not configured, it is automatically generated. If it cannot be generated, an error message is displayed
+	if !options.skipModuleName && config.getOrAutoDetectModuleName() == "" {
+		addProblem("selefra.module-name", `The module name cannot be read. Rectify the fault in one of the following ways:
+		- Make sure your repository is cloned with git from github.com, gitlab.com, bitbucket.org, gitea.com, codeberg.org or a host listed in selefra.git-hosts
+		- Specify the module name in go.mod
+		- Use the flag --module or the environment variable SELEFRA_MODULE_NAME`)
+	}
+
+	// If the output path is not configured, it defaults to SELEFRA_TERRAFORM_OUTPUT_DIRECTORY or the working directory
+	config.Output.getDirectoryOrDefault()
+
+	if layout := config.Output.GetSchemaLayoutOrDefault(); layout != SchemaLayoutSingleFile && layout != SchemaLayoutPerTable {
+		addProblem("output.schema-layout", "Unknown layout %s, it must be %s or %s", layout, SchemaLayoutSingleFile, SchemaLayoutPerTable)
+	}
+
+	if mode := config.Output.GetClientModeOrDefault(); mode != ClientModeSingle && mode != ClientModeMulti {
+		addProblem("output.client-mode", "Unknown client mode %s, it must be %s or %s", mode, ClientModeSingle, ClientModeMulti)
+	}
+
+	problems = append(problems, config.Network.validate()...)
+
+	if config.Output.TemplatesDirectory != "" {
+		if err := CheckTemplatesDirectory(config.Output.TemplatesDirectory); err != nil {
+			addProblem("output.templates-dir", "%s", err.Error())
+		}
+	}
+
+	return problems
+}
+
+// {"a": 1,} -> invalid character '}' looking for beginning of object key string at offset 8
+func describeJsonError(raw string, err error) string {
+	if syntaxError, ok := err.(*json.SyntaxError); ok {
+		return fmt.Sprintf("%s at offset %d of %s", syntaxError.Error(), syntaxError.Offset, raw)
+	}
+	return err.Error()
+}
@@ -0,0 +1,104 @@
+package generate_selefra_terraform_provider
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func Test_checkConfigKeys(t *testing.T) {
+	configYaml := `terraform:
+  provider:
+    repo-url: https://github.com/hashicorp/terraform-provider-aws
+    execute-file:
+      - download-url: https://example.com/foo.zip
+    execute-files:
+      - download-url: https://example.com/foo.zip
+        sha256: ""
+    resource:
+      - aws_vpc
+`
+	problems, err := checkConfigKeys("config.yml", []byte(configYaml))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(problems))
+	assert.Equal(t, "config.yml:4:5: terraform.provider.execute-file: unknown key, did you mean execute-files?", problems[0].String())
+	assert.Equal(t, "config.yml:8:9: terraform.provider.execute-files[0].sha256: unknown key", problems[1].String())
+	assert.Equal(t, "config.yml:9:5: terraform.provider.resource: unknown key, did you mean resources?", problems[2].String())
+
+	// the example configuration has no typos
+	exampleYaml, err := os.ReadFile("../config.yml")
+	assert.Nil(t, err)
+	problems, err = checkConfigKeys("../config.yml", exampleYaml)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(problems))
+}
+
+func Test_validateConfig(t *testing.T) {
+	config := &Config{}
+	config.Selefra.ModuleName = "github.com/selefra/selefra-provider-foo"
+	config.Terraform.TerraformProvider.RepoUrl = "https://github.com/foo/terraform-provider-foo"
+	config.Terraform.TerraformProvider.Config = `{"region": "us-east-1",}`
+	config.Terraform.TerraformProvider.AssetPattern = `^foo-(?P<os>\w+)\.zip$`
+	config.Terraform.TerraformProvider.Platforms = []string{"linux/amd64", "linux"}
+	config.Output.SchemaLayout = "per-file"
+	config.Output.ClientMode = "many"
+	config.keyProblems = []*ConfigProblem{{Location: "config.yml:1:1", Key: "foo", Message: "unknown key"}}
+
+	// every problem is reported at once
+	err := checkConfig(config)
+	assert.True(t, errors.Is(err, ErrCheckConfigFailed))
+	validationError := &ConfigValidationError{}
+	assert.True(t, errors.As(err, &validationError))
+	assert.Equal(t, 6, len(validationError.Problems))
+	assert.Equal(t, "foo", validationError.Problems[0].Key)
+	assert.Equal(t, "terraform.provider.asset-pattern", validationError.Problems[1].Key)
+	assert.Equal(t, "terraform.provider.platforms[1]", validationError.Problems[2].Key)
+	assert.Equal(t, "terraform.provider.config", validationError.Problems[3].Key)
+	assert.Contains(t, validationError.Problems[3].Message, "offset")
+	assert.Equal(t, "output.schema-layout", validationError.Problems[4].Key)
+	assert.Equal(t, "output.client-mode", validationError.Problems[5].Key)
+}
+
+// The published schema must know exactly the keys the configuration is decoded from
+func TestConfigJsonSchema(t *testing.T) {
+	schema := make(map[string]any)
+	assert.Nil(t, json.Unmarshal([]byte(ConfigJsonSchema), &schema))
+	assert.Equal(t, collectStructKeys(reflect.TypeOf(Config{}), ""), collectSchemaKeys(schema, ""))
+}
+
+func collectStructKeys(t reflect.Type, keyPath string) []string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	keys := make([]string, 0)
+	if t.Kind() != reflect.Struct {
+		return keys
+	}
+	for name, field := range configFieldMap(t) {
+		childKeyPath := joinConfigKey(keyPath, name)
+		keys = append(keys, childKeyPath)
+		keys = append(keys, collectStructKeys(field.Type, childKeyPath)...)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func collectSchemaKeys(schema map[string]any, keyPath string) []string {
+	if items, ok := schema["items"].(map[string]any); ok {
+		schema = items
+	}
+	keys := make([]string, 0)
+	properties, _ := schema["properties"].(map[string]any)
+	for name, property := range properties {
+		childKeyPath := joinConfigKey(keyPath, strings.ToLower(name))
+		keys = append(keys, childKeyPath)
+		keys = append(keys, collectSchemaKeys(property.(map[string]any), childKeyPath)...)
+	}
+	sort.Strings(keys)
+	return keys
+}
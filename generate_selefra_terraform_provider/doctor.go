@@ -0,0 +1,348 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	DoctorStatusPass = "pass"
+	DoctorStatusWarn = "warn"
+	DoctorStatusFail = "fail"
+)
+
+// The go version of the go.mod that is generated for the selefra provider
+const doctorMinimumGoVersion = "1.18"
+
+// How long a remote may take to answer the doctor
+const doctorNetworkTimeout = time.Second * 10
+
+// DoctorCheck The result of checking one prerequisite
+type DoctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+
+	// What to do about it, empty when the check passed
+	Hint string `json:"hint,omitempty"`
+}
+
+// DoctorReport The results of all checks in the order they ran
+type DoctorReport struct {
+	Checks []*DoctorCheck `json:"checks"`
+}
+
+func (x *DoctorReport) add(name, status, message, hint string) {
+	x.Checks = append(x.Checks, &DoctorCheck{Name: name, Status: status, Message: message, Hint: hint})
+}
+
+// Err nil when no check failed, the warnings do not count
+func (x *DoctorReport) Err() error {
+	failed := 0
+	for _, check := range x.Checks {
+		if check.Status == DoctorStatusFail {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d of %d doctor checks failed", ErrCheckConfigFailed, failed, len(x.Checks))
+}
+
+// WriteText One line per check, followed by its hint
+func (x *DoctorReport) WriteText(writer io.Writer) error {
+	for _, check := range x.Checks {
+		if _, err := fmt.Fprintf(writer, "[%s] %s: %s\n", strings.ToUpper(check.Status), check.Name, check.Message); err != nil {
+			return err
+		}
+		if check.Hint != "" {
+			if _, err := fmt.Fprintf(writer, "       %s\n", strings.ReplaceAll(check.Hint, "\n", "\n       ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// Doctor Checks the prerequisites of init and generate one by one, so that a problem is found before it fails a run
+// somewhere deep inside
+type Doctor struct {
+	options *ConfigOptions
+
+	// go env GOVERSION, replaced in the tests
+	lookupGoVersion func() (string, error)
+
+	// The resolvers the provider files are resolved with, replaced in the tests
+	providerSourceRegistry *ProviderSourceRegistry
+}
+
+func NewDoctor(options *ConfigOptions) *Doctor {
+	return &Doctor{
+		options:                options,
+		lookupGoVersion:        lookupGoVersion,
+		providerSourceRegistry: GetProviderSourceRegistry(),
+	}
+}
+
+// Run Run every check, a failing check does not stop the ones after it
+func (x *Doctor) Run(ctx context.Context) *DoctorReport {
+	report := &DoctorReport{}
+	x.checkGoToolchain(report)
+
+	config, err := x.options.buildConfig()
+	if err != nil {
+		report.add("config", DoctorStatusFail, err.Error(), "Fix the configuration file given by --config or "+EnvConfigPath)
+		return report
+	}
+	x.checkModuleName(report, config)
+	x.checkConfig(report, config)
+	x.checkPlatform(report, config)
+	x.checkNetwork(ctx, report, config)
+	x.checkProviderDirectory(report, config)
+	return report
+}
+
+func lookupGoVersion() (string, error) {
+	output, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (x *Doctor) checkGoToolchain(report *DoctorReport) {
+	const name = "go toolchain"
+	version, err := x.lookupGoVersion()
+	if err != nil {
+		report.add(name, DoctorStatusFail, fmt.Sprintf("go can not be run: %s", err.Error()),
+			fmt.Sprintf("Install go %s or newer from https://go.dev/dl/ and make sure it is in PATH", doctorMinimumGoVersion))
+		return
+	}
+	// A toolchain built from source, such as devel go1.22-a1b2c3d, has no release number to compare
+	if strings.HasPrefix(version, "devel") {
+		report.add(name, DoctorStatusWarn, fmt.Sprintf("%s is a development toolchain, its version can not be checked", version),
+			fmt.Sprintf("Make sure it is at least go %s, or use a release from https://go.dev/dl/", doctorMinimumGoVersion))
+		return
+	}
+	if compareGoVersion(version, doctorMinimumGoVersion) < 0 {
+		report.add(name, DoctorStatusFail, fmt.Sprintf("%s is older than go%s", version, doctorMinimumGoVersion),
+			fmt.Sprintf("The generated provider needs go %s or newer, upgrade it from https://go.dev/dl/", doctorMinimumGoVersion))
+		return
+	}
+	report.add(name, DoctorStatusPass, version, "")
+}
+
+// go1.21.3 and 1.18 -> 1, like strings.Compare
+func compareGoVersion(a, b string) int {
+	numbersA, numbersB := parseGoVersion(a), parseGoVersion(b)
+	for index := range numbersA {
+		if numbersA[index] < numbersB[index] {
+			return -1
+		}
+		if numbersA[index] > numbersB[index] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// go1.21rc2 -> [1 21 0], the parts that do not parse are 0
+func parseGoVersion(version string) [3]int {
+	version = strings.TrimPrefix(version, "go")
+	if index := strings.IndexFunc(version, func(r rune) bool { return r != '.' && !unicode.IsDigit(r) }); index >= 0 {
+		version = version[:index]
+	}
+	var numbers [3]int
+	for index, part := range strings.SplitN(version, ".", 3) {
+		numbers[index], _ = strconv.Atoi(part)
+	}
+	return numbers
+}
+
+func (x *Doctor) checkModuleName(report *DoctorReport, config *Config) {
+	const name = "module name"
+	if config.Selefra.ModuleName != "" {
+		report.add(name, DoctorStatusPass, fmt.Sprintf("%s is configured", config.Selefra.ModuleName), "")
+		return
+	}
+	if moduleName := strings.TrimSpace(os.Getenv(EnvModuleName)); moduleName != "" {
+		config.Selefra.ModuleName = moduleName
+		report.add(name, DoctorStatusPass, fmt.Sprintf("%s is read from %s", moduleName, EnvModuleName), "")
+		return
+	}
+	if moduleName := config.tryFindGitModuleNameFromGoMod(); moduleName != "" {
+		config.Selefra.ModuleName = moduleName
+		report.add(name, DoctorStatusPass, fmt.Sprintf("%s is read from go.mod", moduleName), "")
+		return
+	}
+
+	hint := "Set selefra.module-name in the configuration file, the --module flag or " + EnvModuleName + ",\n" +
+		"or put the module line in go.mod, or add a git remote: git remote add origin https://github.com/<owner>/<repo>,\n" +
+		"a remote on a host other than " + strings.Join(DefaultGitHosts, ", ") + " needs the host in selefra.git-hosts"
+	repo, gitRepoPath, err := config.openLocalGitRepo()
+	if err != nil {
+		report.add(name, DoctorStatusFail, fmt.Sprintf("it is not configured, not in go.mod and %s is not a git repository", config.Output.getDirectoryOrDefault()), hint)
+		return
+	}
+	remotes, err := repo.Remotes()
+	if err != nil || len(remotes) == 0 {
+		report.add(name, DoctorStatusFail, fmt.Sprintf("it is not configured, not in go.mod and the git repository %s has no remote", gitRepoPath), hint)
+		return
+	}
+	if moduleName := config.tryFindGitModuleNameFromLocalGitRepo(); moduleName != "" {
+		config.Selefra.ModuleName = moduleName
+		report.add(name, DoctorStatusPass, fmt.Sprintf("%s is read from the git remote", moduleName), "")
+		return
+	}
+	report.add(name, DoctorStatusFail, fmt.Sprintf("none of the remotes of the git repository %s is a <owner>/<repo> url on an allowed host", gitRepoPath), hint)
+}
+
+// The module name has a check of its own, so it is not reported again here
+func (x *Doctor) checkConfig(report *DoctorReport, config *Config) {
+	const name = "config"
+	problems := validateConfig(config, configValidationOptions{skipModuleName: true})
+	if len(problems) == 0 {
+		report.add(name, DoctorStatusPass, "the configuration is valid", "")
+		return
+	}
+	lines := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		lines = append(lines, problem.String())
+	}
+	report.add(name, DoctorStatusFail, fmt.Sprintf("%d problem(s) found", len(problems)), strings.Join(lines, "\n"))
+}
+
+func (x *Doctor) checkPlatform(report *DoctorReport, config *Config) {
+	const name = "platform"
+	host := HostPlatform()
+	terraformProvider := &config.Terraform.TerraformProvider
+	if len(terraformProvider.Platforms) != 0 && !isPlatformListed(terraformProvider.Platforms, host) {
+		report.add(name, DoctorStatusWarn, fmt.Sprintf("%s is not in terraform.provider.platforms, the generated provider will not run on this host", host),
+			"Add "+host+" to terraform.provider.platforms")
+		return
+	}
+	executeFiles := terraformProvider.ExecuteFiles
+	if len(executeFiles) == 0 {
+		report.add(name, DoctorStatusPass, fmt.Sprintf("%s, terraform.provider.execute-files is not set, the files are resolved from the releases of the provider", host), "")
+		return
+	}
+	if isPlatformCovered(executeFiles, host) {
+		report.add(name, DoctorStatusPass, fmt.Sprintf("%s is in terraform.provider.execute-files", host), "")
+		return
+	}
+	platforms := make([]string, 0, len(executeFiles))
+	for _, file := range executeFiles {
+		platforms = append(platforms, file.OS+"/"+file.Arch)
+	}
+	report.add(name, DoctorStatusFail, fmt.Sprintf("%s is not in terraform.provider.execute-files, it has %s", host, strings.Join(platforms, ", ")),
+		"Add the file of "+host+" to terraform.provider.execute-files, or remove execute-files to resolve them from the releases")
+}
+
+// Only the remote of the resolver the provider is resolved with is checked, the one resolve would pick
+func (x *Doctor) checkNetwork(ctx context.Context, report *DoctorReport, config *Config) {
+	const name = "network"
+	terraformProvider := &config.Terraform.TerraformProvider
+	if len(terraformProvider.ExecuteFiles) != 0 {
+		report.add(name, DoctorStatusPass, "terraform.provider.execute-files is set, the files are not resolved", "")
+		return
+	}
+	resolver, err := x.providerSourceRegistry.Find(terraformProvider)
+	if err != nil {
+		report.add(name, DoctorStatusWarn, err.Error(), "Fix terraform.provider.repo-url or terraform.provider.source so that the remote can be checked")
+		return
+	}
+	probe, ok := resolver.(ProviderSourceProbe)
+	if !ok {
+		report.add(name, DoctorStatusPass, fmt.Sprintf("the %s resolver asks no remote", resolver.Name()), "")
+		return
+	}
+	probeUrl := probe.ProbeUrl(terraformProvider)
+	if probeUrl == "" {
+		report.add(name, DoctorStatusWarn, fmt.Sprintf("the remote of the %s resolver is not known for %s", resolver.Name(), terraformProvider.RepoUrl), "")
+		return
+	}
+
+	// The remotes are reached the way the resolvers reach them, with the proxy and the certificates of the config, but
+	// without the retries so that the first answer is reported
+	httpClient, err := config.GetHttpClient()
+	if err != nil {
+		report.add(name, DoctorStatusFail, err.Error(), "Fix the network section of the configuration file")
+		return
+	}
+	x.checkReachable(ctx, resty.NewWithClient(httpClient.Client()), report, resolver.Name(), probeUrl)
+}
+
+func (x *Doctor) checkReachable(ctx context.Context, client *resty.Client, report *DoctorReport, name, targetUrl string) {
+	name = "network " + name
+	ctx, cancel := context.WithTimeout(ctx, doctorNetworkTimeout)
+	defer cancel()
+	response, err := client.R().SetContext(ctx).Get(targetUrl)
+	if err != nil {
+		report.add(name, DoctorStatusFail, fmt.Sprintf("%s is not reachable: %s", targetUrl, err.Error()),
+			"Check the network connection, set HTTPS_PROXY or network.proxy if a proxy is needed and network.ca-file if it intercepts TLS")
+		return
+	}
+	switch {
+	case response.StatusCode() == http.StatusForbidden || response.StatusCode() == http.StatusTooManyRequests:
+		report.add(name, DoctorStatusWarn, fmt.Sprintf("%s answered %s, the rate limit may be exceeded", targetUrl, response.Status()),
+			"Wait for the rate limit to reset, or set "+EnvGithubToken+" when it is the github api")
+	case !response.IsSuccess():
+		report.add(name, DoctorStatusWarn, fmt.Sprintf("%s answered %s", targetUrl, response.Status()), "")
+	default:
+		report.add(name, DoctorStatusPass, fmt.Sprintf("%s is reachable", targetUrl), "")
+	}
+}
+
+// The provider directory is written by init and compiled with the tables, a file that does not parse breaks generate
+func (x *Doctor) checkProviderDirectory(report *DoctorReport, config *Config) {
+	const name = "provider directory"
+	providerDirectory := filepath.Join(config.Output.getDirectoryOrDefault(), "provider")
+	exists, err := config.GetFileSystem().Exists(providerDirectory)
+	if err != nil || !exists {
+		report.add(name, DoctorStatusWarn, fmt.Sprintf("%s does not exist", providerDirectory), "Run init to create the project")
+		return
+	}
+	files, err := config.GetFileSystem().ListFiles(providerDirectory)
+	if err != nil {
+		report.add(name, DoctorStatusFail, fmt.Sprintf("list %s error: %s", providerDirectory, err.Error()), "")
+		return
+	}
+
+	fileSet := token.NewFileSet()
+	goFiles := 0
+	problems := make([]string, 0)
+	for _, file := range files {
+		if filepath.Ext(file) != ".go" {
+			continue
+		}
+		goFiles++
+		content, err := config.GetFileSystem().ReadFile(file)
+		if err == nil {
+			_, err = parser.ParseFile(fileSet, file, content, parser.AllErrors)
+		}
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) != 0 {
+		report.add(name, DoctorStatusFail, fmt.Sprintf("%d of %d go files do not parse", len(problems), goFiles),
+			strings.Join(problems, "\n")+"\nFix the files, or remove them and run init again")
+		return
+	}
+	report.add(name, DoctorStatusPass, fmt.Sprintf("%d go files parse", goFiles), "")
+}
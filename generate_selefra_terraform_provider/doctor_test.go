@@ -0,0 +1,163 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompareGoVersion(t *testing.T) {
+	assert.Equal(t, 1, compareGoVersion("go1.21.3", "1.18"))
+	assert.Equal(t, 0, compareGoVersion("go1.18", "1.18"))
+	assert.Equal(t, -1, compareGoVersion("go1.17.13", "1.18"))
+	assert.Equal(t, 1, compareGoVersion("go1.21rc2", "1.18"))
+	assert.Equal(t, 0, compareGoVersion("go1.22.0 X:nocoverageredesign", "1.22"))
+}
+
+func TestDoctor_Run(t *testing.T) {
+	directory := t.TempDir()
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(directory))
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(directory, "provider"), os.ModePerm))
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "provider", "provider.go"), []byte("package provider\n"), os.ModePerm))
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "provider", "client.go"), []byte("package provider\nfunc {"), os.ModePerm))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rate_limit" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	doctor := NewDoctor(&ConfigOptions{
+		TerraformProviderUrl: "https://github.com/selefra/terraform-provider-test",
+		ModuleName:           "github.com/selefra/selefra-provider-test",
+		OutputDirectory:      directory,
+		Logger:               NewNopLogger(),
+	})
+	doctor.lookupGoVersion = func() (string, error) {
+		return "go1.17.13", nil
+	}
+	t.Setenv(EnvGithubApiUrl, server.URL)
+	report := doctor.Run(context.Background())
+
+	statuses := make(map[string]string)
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	assert.Equal(t, map[string]string{
+		"go toolchain":       DoctorStatusFail,
+		"module name":        DoctorStatusPass,
+		"config":             DoctorStatusPass,
+		"platform":           DoctorStatusPass,
+		"network github":     DoctorStatusWarn,
+		"provider directory": DoctorStatusFail,
+	}, statuses)
+	assert.ErrorIs(t, report.Err(), ErrCheckConfigFailed)
+
+	buffer := bytes.Buffer{}
+	assert.Nil(t, report.WriteText(&buffer))
+	assert.Contains(t, buffer.String(), "[FAIL] provider directory: 1 of 2 go files do not parse")
+}
+
+func TestDoctor_checkNetwork(t *testing.T) {
+	requested := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+	}))
+	defer server.Close()
+
+	doctor := NewDoctor(&ConfigOptions{})
+	doctor.providerSourceRegistry = NewProviderSourceRegistry(
+		NewLocalProviderResolver(),
+		NewProviderRegistryResolver(ProviderSourceOpenTofu, server.URL),
+	)
+
+	// Only the registry the provider is on is asked
+	report := &DoctorReport{}
+	config := &Config{}
+	config.Terraform.TerraformProvider.RepoUrl = server.URL[len("http://"):] + "/hashicorp/aws"
+	doctor.checkNetwork(context.Background(), report, config)
+	assert.Equal(t, "network opentofu", report.Checks[0].Name)
+	assert.Equal(t, DoctorStatusPass, report.Checks[0].Status)
+	assert.Equal(t, []string{"/.well-known/terraform.json"}, requested)
+
+	// A local directory needs no network
+	report = &DoctorReport{}
+	config.Terraform.TerraformProvider.RepoUrl = t.TempDir()
+	doctor.checkNetwork(context.Background(), report, config)
+	assert.Equal(t, "network", report.Checks[0].Name)
+	assert.Equal(t, DoctorStatusPass, report.Checks[0].Status)
+	assert.Len(t, requested, 1)
+
+	// Nothing is known to resolve it from
+	report = &DoctorReport{}
+	config.Terraform.TerraformProvider.RepoUrl = "https://example.com/terraform-provider-foo"
+	doctor.checkNetwork(context.Background(), report, config)
+	assert.Equal(t, DoctorStatusWarn, report.Checks[0].Status)
+	assert.Len(t, requested, 1)
+}
+
+func TestDoctor_checkModuleName(t *testing.T) {
+	directory := t.TempDir()
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(directory))
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+	t.Setenv(EnvModuleName, "")
+
+	config := &Config{}
+	config.SetLogger(NewNopLogger())
+	config.Output.Directory = directory
+	report := &DoctorReport{}
+	NewDoctor(&ConfigOptions{}).checkModuleName(report, config)
+	assert.Equal(t, DoctorStatusFail, report.Checks[0].Status)
+	assert.Contains(t, report.Checks[0].Message, "is not a git repository")
+
+	assert.Nil(t, os.WriteFile("go.mod", []byte("module github.com/selefra/selefra-provider-test\n"), os.ModePerm))
+	report = &DoctorReport{}
+	NewDoctor(&ConfigOptions{}).checkModuleName(report, config)
+	assert.Equal(t, DoctorStatusPass, report.Checks[0].Status)
+	assert.Equal(t, "github.com/selefra/selefra-provider-test", config.Selefra.ModuleName)
+}
+
+func TestDoctor_checkPlatform(t *testing.T) {
+	config := &Config{}
+	config.Terraform.TerraformProvider.ExecuteFiles = []*provider.TerraformProviderFile{
+		{OS: "plan9", Arch: "386"},
+	}
+	report := &DoctorReport{}
+	NewDoctor(&ConfigOptions{}).checkPlatform(report, config)
+	assert.Equal(t, DoctorStatusFail, report.Checks[0].Status)
+	assert.Contains(t, report.Checks[0].Message, "plan9/386")
+}
+
+func TestDoctor_checkGoToolchain(t *testing.T) {
+	report := &DoctorReport{}
+	doctor := NewDoctor(&ConfigOptions{})
+	doctor.lookupGoVersion = func() (string, error) {
+		return "", errors.New("executable file not found in $PATH")
+	}
+	doctor.checkGoToolchain(report)
+	assert.Equal(t, DoctorStatusFail, report.Checks[0].Status)
+
+	doctor.lookupGoVersion = func() (string, error) {
+		return "devel go1.22-a1b2c3d Tue Oct 3 12:00:00 2023 +0000", nil
+	}
+	doctor.checkGoToolchain(report)
+	assert.Equal(t, DoctorStatusWarn, report.Checks[1].Status)
+}
@@ -2,4 +2,57 @@ package generate_selefra_terraform_provider
 
 import "errors"
 
-var ErrCheckConfigFailed = errors.New("check config failed")
+// The kinds of errors the scaffold fails with, the errors returned are wrapped around one of them so that
+// errors.Is tells what went wrong, see ExitCode
+var (
+	ErrCheckConfigFailed = errors.New("check config failed")
+
+	// ErrNetwork A request to GitHub, the Terraform registry or another remote failed
+	ErrNetwork = errors.New("network request failed")
+
+	// ErrDownload The executable file of the Terraform provider can not be downloaded
+	ErrDownload = errors.New("download terraform provider failed")
+
+	// ErrBridgeStart The Terraform provider can not be started or configured
+	ErrBridgeStart = errors.New("start terraform provider bridge failed")
+
+	// ErrTemplate A template can not be loaded, checked or rendered
+	ErrTemplate = errors.New("template error")
+
+	// ErrParse A go file, the rendered ones included, can not be parsed
+	ErrParse = errors.New("parse error")
+)
+
+// The process exit codes, 1 is used for any error that is none of the kinds above
+const (
+	ExitCodeOk          = 0
+	ExitCodeUnknown     = 1
+	ExitCodeConfig      = 2
+	ExitCodeNetwork     = 3
+	ExitCodeDownload    = 4
+	ExitCodeBridgeStart = 5
+	ExitCodeTemplate    = 6
+	ExitCodeParse       = 7
+)
+
+// ExitCode The exit code the process should exit with for the error, so that a CI pipeline can tell the failures apart
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitCodeOk
+	case errors.Is(err, ErrCheckConfigFailed):
+		return ExitCodeConfig
+	case errors.Is(err, ErrNetwork):
+		return ExitCodeNetwork
+	case errors.Is(err, ErrDownload):
+		return ExitCodeDownload
+	case errors.Is(err, ErrBridgeStart):
+		return ExitCodeBridgeStart
+	case errors.Is(err, ErrTemplate):
+		return ExitCodeTemplate
+	case errors.Is(err, ErrParse):
+		return ExitCodeParse
+	default:
+		return ExitCodeUnknown
+	}
+}
@@ -0,0 +1,22 @@
+package generate_selefra_terraform_provider
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitCodeOk, ExitCode(nil))
+	assert.Equal(t, ExitCodeUnknown, ExitCode(errors.New("foo")))
+	assert.Equal(t, ExitCodeConfig, ExitCode(fmt.Errorf("create config failed: %w", ErrCheckConfigFailed)))
+	assert.Equal(t, ExitCodeNetwork, ExitCode(fmt.Errorf("%w: request foo failed", ErrNetwork)))
+	assert.Equal(t, ExitCodeDownload, ExitCode(fmt.Errorf("%w: foo", ErrDownload)))
+	assert.Equal(t, ExitCodeBridgeStart, ExitCode(fmt.Errorf("%w: foo", ErrBridgeStart)))
+	assert.Equal(t, ExitCodeTemplate, ExitCode(fmt.Errorf("run generate failed: %w", fmt.Errorf("%w: foo", ErrTemplate))))
+
+	// the kind survives formatting the generated code
+	_, err := formatGoSource("main.go", []byte("package main\n\nfunc main() {\n"))
+	assert.Equal(t, ExitCodeParse, ExitCode(err))
+}
@@ -0,0 +1,369 @@
+package generate_selefra_terraform_provider
+
+import (
+	"fmt"
+	"github.com/pmezard/go-difflib/difflib"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileSystem All reads and writes of the generated project go through this interface, so that the output can be
+// redirected somewhere other than the disk, for example into memory when running in dry-run mode
+type FileSystem interface {
+	ReadFile(path string) ([]byte, error)
+	WriteFile(path string, data []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+	RemoveAll(path string) error
+	Exists(path string) (bool, error)
+
+	// ListFiles Recursively lists all regular files under the directory, in dictionary order
+	ListFiles(directory string) ([]string, error)
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// OsFileSystem Read and write directly to the disk, this is the default behavior
+type OsFileSystem struct {
+}
+
+var _ FileSystem = &OsFileSystem{}
+
+func NewOsFileSystem() *OsFileSystem {
+	return &OsFileSystem{}
+}
+
+func (x *OsFileSystem) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (x *OsFileSystem) WriteFile(path string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(path, data, perm)
+}
+
+func (x *OsFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (x *OsFileSystem) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+func (x *OsFileSystem) Exists(path string) (bool, error) {
+	return PathExists(path)
+}
+
+func (x *OsFileSystem) ListFiles(directory string) ([]string, error) {
+	files := make([]string, 0)
+	if exists, err := PathExists(directory); err != nil || !exists {
+		return files, err
+	}
+	err := filepath.Walk(directory, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// MemoryFileSystem Writes are kept in memory and reads fall through to the underlying file system for anything that
+// has not been touched, so the generators behave exactly as they would on disk while the tree stays untouched
+type MemoryFileSystem struct {
+	base FileSystem
+
+	// absolute path -> content of the file written
+	files map[string][]byte
+
+	// absolute paths of the directories that were removed, anything below them in base is considered gone
+	removedDirectories []string
+}
+
+var _ FileSystem = &MemoryFileSystem{}
+
+func NewMemoryFileSystem(base FileSystem) *MemoryFileSystem {
+	return &MemoryFileSystem{
+		base:  base,
+		files: make(map[string][]byte),
+	}
+}
+
+func (x *MemoryFileSystem) ReadFile(path string) ([]byte, error) {
+	absPath := toAbsPath(path)
+	if content, exists := x.files[absPath]; exists {
+		return content, nil
+	}
+	if x.isRemoved(absPath) {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return x.base.ReadFile(path)
+}
+
+func (x *MemoryFileSystem) WriteFile(path string, data []byte, perm os.FileMode) error {
+	content := make([]byte, len(data))
+	copy(content, data)
+	x.files[toAbsPath(path)] = content
+	return nil
+}
+
+func (x *MemoryFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	// Directories are implied by the files they contain
+	return nil
+}
+
+func (x *MemoryFileSystem) RemoveAll(path string) error {
+	absPath := toAbsPath(path)
+	for filePath := range x.files {
+		if isPathUnder(filePath, absPath) {
+			delete(x.files, filePath)
+		}
+	}
+	x.removedDirectories = append(x.removedDirectories, absPath)
+	return nil
+}
+
+func (x *MemoryFileSystem) Exists(path string) (bool, error) {
+	absPath := toAbsPath(path)
+	for filePath := range x.files {
+		if isPathUnder(filePath, absPath) {
+			return true, nil
+		}
+	}
+	if x.isRemoved(absPath) {
+		return false, nil
+	}
+	return x.base.Exists(path)
+}
+
+func (x *MemoryFileSystem) ListFiles(directory string) ([]string, error) {
+	absDirectory := toAbsPath(directory)
+	fileSet := make(map[string]struct{})
+	baseFiles, err := x.base.ListFiles(directory)
+	if err != nil {
+		return nil, err
+	}
+	for _, baseFile := range baseFiles {
+		if !x.isRemoved(toAbsPath(baseFile)) {
+			fileSet[filepath.Clean(baseFile)] = struct{}{}
+		}
+	}
+	for filePath := range x.files {
+		if !isPathUnder(filePath, absDirectory) {
+			continue
+		}
+		// keep the same form of path as the caller gave us
+		relativePath, err := filepath.Rel(absDirectory, filePath)
+		if err != nil {
+			return nil, err
+		}
+		fileSet[filepath.Join(directory, relativePath)] = struct{}{}
+	}
+	files := make([]string, 0, len(fileSet))
+	for filePath := range fileSet {
+		files = append(files, filePath)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (x *MemoryFileSystem) isRemoved(absPath string) bool {
+	for _, removedDirectory := range x.removedDirectories {
+		if isPathUnder(absPath, removedDirectory) {
+			return true
+		}
+	}
+	return false
+}
+
+// FileChangeType What happened to a file compared to the disk
+type FileChangeType string
+
+const (
+	FileChangeTypeCreated FileChangeType = "created"
+	FileChangeTypeChanged FileChangeType = "changed"
+	FileChangeTypeDeleted FileChangeType = "deleted"
+)
+
+// FileChange A file that would differ from the disk if the memory file system were flushed
+type FileChange struct {
+	Path       string
+	ChangeType FileChangeType
+	OldContent []byte
+	NewContent []byte
+}
+
+// Changes Compare everything kept in memory with the underlying file system, files whose content is unchanged are omitted
+func (x *MemoryFileSystem) Changes() ([]*FileChange, error) {
+	changes := make([]*FileChange, 0)
+	for absPath, content := range x.files {
+		path := toDisplayPath(absPath)
+		oldContent, err := x.base.ReadFile(absPath)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+			changes = append(changes, &FileChange{Path: path, ChangeType: FileChangeTypeCreated, NewContent: content})
+			continue
+		}
+		if string(oldContent) != string(content) {
+			changes = append(changes, &FileChange{Path: path, ChangeType: FileChangeTypeChanged, OldContent: oldContent, NewContent: content})
+		}
+	}
+	for _, removedDirectory := range x.removedDirectories {
+		baseFiles, err := x.base.ListFiles(removedDirectory)
+		if err != nil {
+			return nil, err
+		}
+		for _, baseFile := range baseFiles {
+			absPath := toAbsPath(baseFile)
+			if _, exists := x.files[absPath]; exists {
+				continue
+			}
+			oldContent, err := x.base.ReadFile(baseFile)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, &FileChange{Path: toDisplayPath(absPath), ChangeType: FileChangeTypeDeleted, OldContent: oldContent})
+		}
+	}
+	// A directory may be removed more than once, so the same deletion can show up twice
+	changeSet := make(map[string]*FileChange)
+	for _, change := range changes {
+		changeSet[change.Path] = change
+	}
+	changes = changes[:0]
+	for _, change := range changeSet {
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+// WriteDryRunReport Print a unified diff of every change followed by a summary of created, changed and deleted files
+func (x *MemoryFileSystem) WriteDryRunReport(writer io.Writer) error {
+	changes, err := x.Changes()
+	if err != nil {
+		return err
+	}
+	createdCount, changedCount, deletedCount := 0, 0, 0
+	for _, change := range changes {
+		fromFile, toFile := "a/"+change.Path, "b/"+change.Path
+		switch change.ChangeType {
+		case FileChangeTypeCreated:
+			createdCount++
+			fromFile = "/dev/null"
+		case FileChangeTypeChanged:
+			changedCount++
+		case FileChangeTypeDeleted:
+			deletedCount++
+			toFile = "/dev/null"
+		}
+		unifiedDiff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(change.OldContent)),
+			B:        difflib.SplitLines(string(change.NewContent)),
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(writer, "diff %s %s\n%s", fromFile, toFile, unifiedDiff); err != nil {
+			return err
+		}
+	}
+
+	buff := strings.Builder{}
+	buff.WriteString(fmt.Sprintf("\ndry run, nothing was written: %d created, %d changed, %d deleted\n", createdCount, changedCount, deletedCount))
+	for _, change := range changes {
+		buff.WriteString(fmt.Sprintf("\t%-8s %s\n", change.ChangeType, change.Path))
+	}
+	_, err = io.WriteString(writer, buff.String())
+	return err
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// RecordingFileSystem Passes everything through to the underlying file system and remembers the files that were
+// written, so that a command can report them
+type RecordingFileSystem struct {
+	FileSystem
+
+	lock sync.Mutex
+
+	// absolute paths of the files written
+	writtenSet map[string]struct{}
+}
+
+var _ FileSystem = &RecordingFileSystem{}
+
+func NewRecordingFileSystem(base FileSystem) *RecordingFileSystem {
+	return &RecordingFileSystem{
+		FileSystem: base,
+		writtenSet: make(map[string]struct{}),
+	}
+}
+
+func (x *RecordingFileSystem) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if err := x.FileSystem.WriteFile(path, data, perm); err != nil {
+		return err
+	}
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	x.writtenSet[toAbsPath(path)] = struct{}{}
+	return nil
+}
+
+// WrittenFiles The files written so far, in dictionary order, each one only once however often it was written
+func (x *RecordingFileSystem) WrittenFiles() []string {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	writtenFiles := make([]string, 0, len(x.writtenSet))
+	for absPath := range x.writtenSet {
+		writtenFiles = append(writtenFiles, toDisplayPath(absPath))
+	}
+	sort.Strings(writtenFiles)
+	return writtenFiles
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+func toAbsPath(path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return absPath
+}
+
+// Paths are shown relative to the working directory where possible, which is what the user passed in most of the time
+func toDisplayPath(absPath string) string {
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(absPath)
+	}
+	relativePath, err := filepath.Rel(workingDirectory, absPath)
+	if err != nil || strings.HasPrefix(relativePath, "..") {
+		return filepath.ToSlash(absPath)
+	}
+	return filepath.ToSlash(relativePath)
+}
+
+func isPathUnder(path, directory string) bool {
+	return path == directory || strings.HasPrefix(path, directory+string(filepath.Separator))
+}
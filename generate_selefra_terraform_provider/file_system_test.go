@@ -0,0 +1,73 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMemoryFileSystem_Changes(t *testing.T) {
+	directory := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(directory, "resources"), os.ModePerm))
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "go.mod"), []byte("module foo\n"), os.ModePerm))
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "main.go"), []byte("package main\n"), os.ModePerm))
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "resources", "old.go"), []byte("package resources\n"), os.ModePerm))
+
+	memoryFileSystem := NewMemoryFileSystem(NewOsFileSystem())
+	assert.Nil(t, memoryFileSystem.RemoveAll(filepath.Join(directory, "resources")))
+	assert.Nil(t, memoryFileSystem.WriteFile(filepath.Join(directory, "resources", "new.go"), []byte("package resources\n"), os.ModePerm))
+	assert.Nil(t, memoryFileSystem.WriteFile(filepath.Join(directory, "go.mod"), []byte("module bar\n"), os.ModePerm))
+	assert.Nil(t, memoryFileSystem.WriteFile(filepath.Join(directory, "main.go"), []byte("package main\n"), os.ModePerm))
+
+	// reads see the writes, the disk does not
+	content, err := memoryFileSystem.ReadFile(filepath.Join(directory, "go.mod"))
+	assert.Nil(t, err)
+	assert.Equal(t, "module bar\n", string(content))
+	content, err = os.ReadFile(filepath.Join(directory, "go.mod"))
+	assert.Nil(t, err)
+	assert.Equal(t, "module foo\n", string(content))
+	exists, err := memoryFileSystem.Exists(filepath.Join(directory, "resources", "old.go"))
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	files, err := memoryFileSystem.ListFiles(filepath.Join(directory, "resources"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(directory, "resources", "new.go")}, files)
+
+	changes, err := memoryFileSystem.Changes()
+	assert.Nil(t, err)
+	changeTypeMap := make(map[string]FileChangeType)
+	for _, change := range changes {
+		changeTypeMap[filepath.Base(change.Path)] = change.ChangeType
+	}
+	assert.Equal(t, map[string]FileChangeType{
+		"go.mod": FileChangeTypeChanged,
+		"new.go": FileChangeTypeCreated,
+		"old.go": FileChangeTypeDeleted,
+	}, changeTypeMap)
+
+	buff := bytes.Buffer{}
+	assert.Nil(t, memoryFileSystem.WriteDryRunReport(&buff))
+	assert.Contains(t, buff.String(), "-module foo\n+module bar\n")
+	assert.Contains(t, buff.String(), "1 created, 1 changed, 1 deleted")
+}
+
+func TestRecordingFileSystem(t *testing.T) {
+	directory := t.TempDir()
+	recordingFileSystem := NewRecordingFileSystem(NewMemoryFileSystem(NewOsFileSystem()))
+
+	assert.Nil(t, recordingFileSystem.WriteFile(filepath.Join(directory, "b.go"), []byte("b"), 0644))
+	assert.Nil(t, recordingFileSystem.WriteFile(filepath.Join(directory, "a.go"), []byte("a"), 0644))
+	assert.Nil(t, recordingFileSystem.WriteFile(filepath.Join(directory, "a.go"), []byte("a2"), 0644))
+	content, err := recordingFileSystem.ReadFile(filepath.Join(directory, "a.go"))
+	assert.Nil(t, err)
+	assert.Equal(t, "a2", string(content))
+
+	writtenFiles := recordingFileSystem.WrittenFiles()
+	assert.Equal(t, 2, len(writtenFiles))
+	assert.True(t, strings.HasSuffix(writtenFiles[0], "a.go"))
+	assert.True(t, strings.HasSuffix(writtenFiles[1], "b.go"))
+}
@@ -0,0 +1,237 @@
+package generate_selefra_terraform_provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultGithubApiUrl The API of github.com, a GitHub Enterprise server has its own, https://github.example.com/api/v3
+const DefaultGithubApiUrl = "https://api.github.com"
+
+const (
+
+	// EnvGithubToken The token the GitHub API is called with, an unauthenticated client only has 60 requests per hour
+	EnvGithubToken = "GITHUB_TOKEN"
+
+	// EnvGithubApiUrl Same as terraform.provider.github-api-url, GitHub Actions sets it to the API of the server it runs on
+	EnvGithubApiUrl = "GITHUB_API_URL"
+)
+
+const (
+
+	// How many times a request that failed on the network or with a 5xx is sent
+	githubMaxAttempts = 3
+
+	// A rate limit that resets later than this fails the request instead of waiting for it
+	githubMaxRateLimitWait = time.Minute
+
+	// Releases are listed in pages of this size, and at most this many pages are looked through for a tag
+	githubReleasesPerPage = 100
+	githubMaxReleasePages = 10
+)
+
+// GithubClient Calls the GitHub REST API with a token if one is given, waits for a rate limit that resets soon and
+// fails fast on any other 4xx
+type GithubClient struct {
+	apiUrl string
+	token  string
+	client *resty.Client
+	logger Logger
+
+	// time.Sleep, replaced in the tests
+	sleep func(duration time.Duration)
+}
+
+func NewGithubClient(apiUrl, token string, logger Logger) *GithubClient {
+	if apiUrl == "" {
+		apiUrl = DefaultGithubApiUrl
+	}
+	return &GithubClient{
+		apiUrl: strings.TrimRight(apiUrl, "/"),
+		token:  token,
+		client: resty.NewWithClient(defaultHttpClient.Client()),
+		logger: logger,
+		sleep:  time.Sleep,
+	}
+}
+
+// SetHttpClient Send the requests through the proxy and the certificates of the given client, the retries stay the
+// ones of GithubClient because they know about the rate limit
+func (x *GithubClient) SetHttpClient(httpClient *HttpClient) *GithubClient {
+	x.client = resty.NewWithClient(httpClient.Client())
+	return x
+}
+
+// GetRelease The latest release of the repository, or the release of the given version, whose tag may or may not have
+// the v prefix. repository is owner/repo
+func (x *GithubClient) GetRelease(repository, version string) (*GithubLatestReleasesResponse, error) {
+	if version == "" {
+		release := &GithubLatestReleasesResponse{}
+		if _, err := x.getJson("/repos/"+repository+"/releases/latest", release); err != nil {
+			return nil, err
+		}
+		return release, nil
+	}
+
+	// The releases are listed newest first, so a recent tag is found on the first page
+	tags := map[string]struct{}{strings.TrimPrefix(version, "v"): {}, "v" + strings.TrimPrefix(version, "v"): {}}
+	var found *GithubLatestReleasesResponse
+	err := x.rangeReleases(repository, func(release *GithubLatestReleasesResponse) bool {
+		if _, ok := tags[release.TagName]; ok {
+			found = release
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: repository %s has no release tagged %s", ErrCheckConfigFailed, repository, version)
+	}
+	return found, nil
+}
+
+// ListReleases The releases of the repository newest first, as many as fit on the pages that are looked through
+func (x *GithubClient) ListReleases(repository string) ([]*GithubLatestReleasesResponse, error) {
+	releases := make([]*GithubLatestReleasesResponse, 0)
+	err := x.rangeReleases(repository, func(release *GithubLatestReleasesResponse) bool {
+		releases = append(releases, release)
+		return true
+	})
+	return releases, err
+}
+
+// Page through the releases until f returns false
+func (x *GithubClient) rangeReleases(repository string, f func(release *GithubLatestReleasesResponse) bool) error {
+	path := fmt.Sprintf("/repos/%s/releases?per_page=%d", repository, githubReleasesPerPage)
+	for page := 1; page <= githubMaxReleasePages && path != ""; page++ {
+		releases := make([]*GithubLatestReleasesResponse, 0)
+		response, err := x.getJson(path, &releases)
+		if err != nil {
+			return err
+		}
+		for _, release := range releases {
+			if !f(release) {
+				return nil
+			}
+		}
+		path = x.nextPage(response)
+	}
+	return nil
+}
+
+// The path of the next page from the Link header, empty on the last page
+func (x *GithubClient) nextPage(response *resty.Response) string {
+	for _, link := range strings.Split(response.Header().Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 || strings.TrimSpace(parts[1]) != `rel="next"` {
+			continue
+		}
+		nextUrl := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+		return strings.TrimPrefix(nextUrl, x.apiUrl)
+	}
+	return ""
+}
+
+func (x *GithubClient) getJson(path string, v any) (*resty.Response, error) {
+	response, err := x.get(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(response.Body(), v); err != nil {
+		return nil, fmt.Errorf("%w: github response of %s json unmarshal failed: %s", ErrNetwork, path, err.Error())
+	}
+	return response, nil
+}
+
+// Send the request again when the network or the server failed, or when the rate limit resets soon enough
+func (x *GithubClient) get(path string) (*resty.Response, error) {
+	targetUrl := x.apiUrl + path
+	rateLimitWaited := false
+	for attempt := 1; ; attempt++ {
+		request := x.client.R().
+			SetHeader("Accept", "application/vnd.github+json").
+			SetHeader("X-GitHub-Api-Version", "2022-11-28")
+		if x.token != "" {
+			request.SetHeader("Authorization", "Bearer "+x.token)
+		}
+		x.logger.Info("request github api %s", targetUrl)
+		response, err := request.Get(targetUrl)
+
+		switch {
+		case err != nil || response.StatusCode() >= 500:
+			reason := ""
+			if err != nil {
+				reason = err.Error()
+			} else {
+				reason = response.Status()
+			}
+			if attempt >= githubMaxAttempts {
+				return nil, fmt.Errorf("%w: request %s failed %d times, the last time: %s", ErrNetwork, targetUrl, attempt, reason)
+			}
+			backoff := time.Second << (attempt - 1)
+			x.logger.Warn("request %s failed: %s, retry in %s", targetUrl, reason, backoff)
+			x.sleep(backoff)
+		case response.IsSuccess():
+			return response, nil
+		default:
+			wait, isRateLimit := x.rateLimitWait(response)
+			if !isRateLimit {
+				return nil, fmt.Errorf("%w: request %s failed: %s", ErrNetwork, targetUrl, response.Status())
+			}
+			if rateLimitWaited || wait > githubMaxRateLimitWait {
+				hint := ""
+				if x.token == "" {
+					hint = ", set " + EnvGithubToken + " to get a higher rate limit"
+				}
+				return nil, fmt.Errorf("%w: the github api rate limit is exceeded, it resets in %s%s", ErrNetwork, wait.Round(time.Second), hint)
+			}
+			x.logger.Warn("the github api rate limit is exceeded, wait %s for it to reset", wait.Round(time.Second))
+			x.sleep(wait)
+			rateLimitWaited = true
+		}
+	}
+}
+
+// How long to wait before the rate limit allows the next request, false if the response is not about the rate limit
+func (x *GithubClient) rateLimitWait(response *resty.Response) (time.Duration, bool) {
+	if response.StatusCode() != http.StatusForbidden && response.StatusCode() != http.StatusTooManyRequests {
+		return 0, false
+	}
+	header := response.Header()
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+	if header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			wait := time.Until(time.Unix(reset, 0)) + time.Second
+			if wait < 0 {
+				wait = 0
+			}
+			return wait, true
+		}
+	}
+	return 0, false
+}
+
+// The host of the web interface of the API, github.com for api.github.com
+func githubHostOfApiUrl(apiUrl string) string {
+	parse, err := url.Parse(apiUrl)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(parse.Hostname())
+	if host == "api.github.com" {
+		return "github.com"
+	}
+	return host
+}
@@ -0,0 +1,123 @@
+package generate_selefra_terraform_provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestGithubClient(server *httptest.Server) (*GithubClient, *[]time.Duration) {
+	sleeps := make([]time.Duration, 0)
+	client := NewGithubClient(server.URL+"/api/v3/", "test-token", NewNopLogger())
+	client.sleep = func(duration time.Duration) {
+		sleeps = append(sleeps, duration)
+	}
+	return client, &sleeps
+}
+
+func TestGithubClient_GetRelease(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v3/repos/selefra/terraform-provider-test/releases/latest":
+			_, _ = fmt.Fprint(w, `{"tag_name": "v1.2.0"}`)
+		case "/api/v3/repos/selefra/terraform-provider-test/releases":
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/selefra/terraform-provider-test/releases?per_page=100&page=2>; rel="next", <%s/api/v3/repos/selefra/terraform-provider-test/releases?per_page=100&page=2>; rel="last"`, server.URL, server.URL))
+				_, _ = fmt.Fprint(w, `[{"tag_name": "v1.2.0"}, {"tag_name": "v1.1.0"}]`)
+			} else {
+				_, _ = fmt.Fprint(w, `[{"tag_name": "v1.0.0"}]`)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, _ := newTestGithubClient(server)
+
+	release, err := client.GetRelease("selefra/terraform-provider-test", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1.2.0", release.TagName)
+
+	release, err = client.GetRelease("selefra/terraform-provider-test", "1.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1.0.0", release.TagName)
+
+	_, err = client.GetRelease("selefra/terraform-provider-test", "0.9.0")
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+
+	_, err = client.GetRelease("selefra/terraform-provider-missing", "")
+	assert.ErrorIs(t, err, ErrNetwork)
+}
+
+func TestGithubClient_get(t *testing.T) {
+	requests := 0
+	status := http.StatusOK
+	header := http.Header{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		// A rate limit only lasts for the first request
+		if requests == 1 || status != http.StatusForbidden {
+			w.WriteHeader(status)
+		}
+		_, _ = fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	// Waits for a rate limit that resets soon
+	client, sleeps := newTestGithubClient(server)
+	status, header = http.StatusForbidden, http.Header{"Retry-After": {"5"}}
+	_, err := client.get("/rate_limit")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, []time.Duration{5 * time.Second}, *sleeps)
+
+	// Fails fast when it resets too late
+	requests = 0
+	client, sleeps = newTestGithubClient(server)
+	header = http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)}}
+	_, err = client.get("/rate_limit")
+	assert.ErrorIs(t, err, ErrNetwork)
+	assert.Contains(t, err.Error(), "rate limit")
+	assert.Equal(t, 1, requests)
+	assert.Empty(t, *sleeps)
+
+	// No retry on any other 4xx
+	requests = 0
+	client, sleeps = newTestGithubClient(server)
+	status, header = http.StatusNotFound, http.Header{}
+	_, err = client.get("/repos/selefra/missing")
+	assert.ErrorIs(t, err, ErrNetwork)
+	assert.Equal(t, 1, requests)
+	assert.Empty(t, *sleeps)
+
+	// 5xx is retried with a backoff
+	requests = 0
+	client, sleeps = newTestGithubClient(server)
+	status = http.StatusBadGateway
+	_, err = client.get("/repos/selefra/test")
+	assert.ErrorIs(t, err, ErrNetwork)
+	assert.Equal(t, githubMaxAttempts, requests)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *sleeps)
+}
+
+func TestTerraformProvider_IsGithubRepo(t *testing.T) {
+	t.Setenv(EnvGithubApiUrl, "")
+	terraformProvider := &TerraformProvider{RepoUrl: "https://github.example.com/infra/terraform-provider-test"}
+	isGithubRepo, err := terraformProvider.IsGithubRepo()
+	assert.Nil(t, err)
+	assert.False(t, isGithubRepo)
+
+	terraformProvider.GithubApiUrl = "https://github.example.com/api/v3"
+	isGithubRepo, err = terraformProvider.IsGithubRepo()
+	assert.Nil(t, err)
+	assert.True(t, isGithubRepo)
+}
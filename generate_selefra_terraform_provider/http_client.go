@@ -0,0 +1,372 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/go-getter"
+	"golang.org/x/net/http/httpproxy"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultHttpTimeout      = time.Minute
+	DefaultHttpRetries      = 3
+	DefaultHttpRetryWait    = time.Second
+	DefaultHttpRetryMaxWait = time.Second * 30
+)
+
+// Sent with every request, so that the remotes can tell the scaffold from a browser or from terraform
+const httpUserAgent = "selefra-terraform-provider-scaffolding"
+
+// Network How the remotes are reached, the proxy of the environment and the certificates of the system are used when
+// nothing is set
+type Network struct {
+
+	// The proxy every request goes through, http://proxy.example.com:3128. HTTPS_PROXY, HTTP_PROXY and NO_PROXY are
+	// used if not set
+	Proxy string `mapstructure:"proxy" json:"proxy"`
+
+	// The hosts that are reached without the proxy, example.com also covers its subdomains
+	NoProxy []string `mapstructure:"no-proxy" json:"no_proxy"`
+
+	// A PEM file of the certificates that are trusted besides the ones of the system, for a proxy that intercepts TLS
+	CaFile string `mapstructure:"ca-file" json:"ca_file"`
+
+	// How long a request may take, a go duration like 90s, DefaultHttpTimeout if not set
+	Timeout string `mapstructure:"timeout" json:"timeout"`
+
+	// How many times a request that failed on the network or with 429 or 5xx is retried, DefaultHttpRetries if not set,
+	// a negative number to never retry
+	Retries int `mapstructure:"retries" json:"retries"`
+
+	// The wait before the first retry, it doubles with every retry up to retry-max-wait
+	RetryWait string `mapstructure:"retry-wait" json:"retry_wait"`
+
+	RetryMaxWait string `mapstructure:"retry-max-wait" json:"retry_max_wait"`
+}
+
+func (x *Network) getTimeout() (time.Duration, error) {
+	return parseNetworkDuration(x.Timeout, DefaultHttpTimeout)
+}
+
+func (x *Network) getRetryWait() (time.Duration, error) {
+	return parseNetworkDuration(x.RetryWait, DefaultHttpRetryWait)
+}
+
+func (x *Network) getRetryMaxWait() (time.Duration, error) {
+	return parseNetworkDuration(x.RetryMaxWait, DefaultHttpRetryMaxWait)
+}
+
+func (x *Network) getRetries() int {
+	switch {
+	case x.Retries < 0:
+		return 0
+	case x.Retries == 0:
+		return DefaultHttpRetries
+	default:
+		return x.Retries
+	}
+}
+
+func parseNetworkDuration(value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("%s is not positive", value)
+	}
+	return duration, nil
+}
+
+// The proxy of the config, or the one of the environment
+func (x *Network) getProxyFunc() (func(request *http.Request) (*url.URL, error), error) {
+	if x.Proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	if _, err := url.Parse(x.Proxy); err != nil {
+		return nil, err
+	}
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  x.Proxy,
+		HTTPSProxy: x.Proxy,
+		NoProxy:    strings.Join(x.NoProxy, ","),
+	}).ProxyFunc()
+	return func(request *http.Request) (*url.URL, error) {
+		return proxyFunc(request.URL)
+	}, nil
+}
+
+// The certificates of the system together with the ones of ca-file, nil to use the ones of the system only
+func (x *Network) getRootCAs() (*x509.CertPool, error) {
+	if x.CaFile == "" {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(x.CaFile)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificate is found in %s", x.CaFile)
+	}
+	return pool, nil
+}
+
+// Override the settings with the ones that are set on the other network
+func (x *Network) merge(other *Network) {
+	if other.Proxy != "" {
+		x.Proxy = other.Proxy
+	}
+	if len(other.NoProxy) != 0 {
+		x.NoProxy = other.NoProxy
+	}
+	if other.CaFile != "" {
+		x.CaFile = other.CaFile
+	}
+	if other.Timeout != "" {
+		x.Timeout = other.Timeout
+	}
+	if other.Retries != 0 {
+		x.Retries = other.Retries
+	}
+	if other.RetryWait != "" {
+		x.RetryWait = other.RetryWait
+	}
+	if other.RetryMaxWait != "" {
+		x.RetryMaxWait = other.RetryMaxWait
+	}
+}
+
+// Every setting that can not be used, all of them at once
+func (x *Network) validate() []*ConfigProblem {
+	problems := make([]*ConfigProblem, 0)
+	addProblem := func(key, format string, args ...any) {
+		problems = append(problems, &ConfigProblem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+	if x.Proxy != "" {
+		if parse, err := url.Parse(x.Proxy); err != nil || parse.Host == "" {
+			addProblem("network.proxy", "%s is not a proxy url like http://proxy.example.com:3128", x.Proxy)
+		}
+	}
+	if _, err := x.getRootCAs(); err != nil {
+		addProblem("network.ca-file", "%s", err.Error())
+	}
+	if _, err := x.getTimeout(); err != nil {
+		addProblem("network.timeout", "%s is not a duration like 90s: %s", x.Timeout, err.Error())
+	}
+	if _, err := x.getRetryWait(); err != nil {
+		addProblem("network.retry-wait", "%s is not a duration like 1s: %s", x.RetryWait, err.Error())
+	}
+	if _, err := x.getRetryMaxWait(); err != nil {
+		addProblem("network.retry-max-wait", "%s is not a duration like 30s: %s", x.RetryMaxWait, err.Error())
+	}
+	return problems
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// HttpClient The client every request to a remote goes through, the resolvers, the GitHub API, the registries and the
+// download of the provider, so that they share the proxy, the certificates, the timeout and the retry policy
+type HttpClient struct {
+	client *http.Client
+	resty  *resty.Client
+
+	retries      int
+	retryWait    time.Duration
+	retryMaxWait time.Duration
+}
+
+func NewHttpClient(network *Network) (*HttpClient, error) {
+	if problems := network.validate(); len(problems) != 0 {
+		return nil, &ConfigValidationError{Problems: problems}
+	}
+	// The errors are already checked by validate
+	proxyFunc, _ := network.getProxyFunc()
+	rootCAs, _ := network.getRootCAs()
+	timeout, _ := network.getTimeout()
+	retryWait, _ := network.getRetryWait()
+	retryMaxWait, _ := network.getRetryMaxWait()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc
+	transport.ResponseHeaderTimeout = timeout
+	if rootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+	}
+	client := &http.Client{
+		Transport: &userAgentTransport{next: transport},
+		Timeout:   timeout,
+	}
+	x := &HttpClient{
+		client:       client,
+		retries:      network.getRetries(),
+		retryWait:    retryWait,
+		retryMaxWait: retryMaxWait,
+	}
+	x.resty = resty.NewWithClient(client).
+		SetRetryCount(x.retries).
+		SetRetryWaitTime(retryWait).
+		SetRetryMaxWaitTime(retryMaxWait).
+		AddRetryCondition(func(response *resty.Response, err error) bool {
+			return err != nil || response.StatusCode() == http.StatusTooManyRequests || response.StatusCode() >= http.StatusInternalServerError
+		})
+	return x, nil
+}
+
+// R A request that is retried by the policy of the client
+func (x *HttpClient) R(ctx context.Context) *resty.Request {
+	return x.resty.R().SetContext(ctx)
+}
+
+// Client The underlying client, for the callers that retry by themselves
+func (x *HttpClient) Client() *http.Client {
+	return x.client
+}
+
+// Download Fetch the url into the directory, an archive is unpacked. sha256Sum is the checksum of the file at the url in
+// hex, it is not checked when empty. A download that failed on the network or with 429 or 5xx is retried by the policy
+// of the client, any other failure such as 404 or a file that does not match the checksum is returned
+func (x *HttpClient) Download(ctx context.Context, downloadUrl, sha256Sum, directory string) error {
+	source, err := url.Parse(downloadUrl)
+	if err != nil {
+		return err
+	}
+	if sha256Sum != "" {
+		// go-getter checks the file before it unpacks it and removes the parameter from the request
+		checksum := "checksum=" + url.QueryEscape("sha256:"+sha256Sum)
+		if source.RawQuery == "" {
+			source.RawQuery = checksum
+		} else {
+			source.RawQuery += "&" + checksum
+		}
+	}
+
+	wait := x.retryWait
+	for attempt := 0; attempt <= x.retries; attempt++ {
+		if attempt != 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			if wait *= 2; wait > x.retryMaxWait {
+				wait = x.retryMaxWait
+			}
+		}
+
+		getters := make(map[string]getter.Getter, len(getter.Getters))
+		for scheme, g := range getter.Getters {
+			getters[scheme] = g
+		}
+		// A provider takes long to download, only the wait for the response is limited
+		transport := &downloadRetryTransport{next: x.client.Transport}
+		httpGetter := &getter.HttpGetter{Client: &http.Client{Transport: transport}, Netrc: true}
+		getters["http"] = httpGetter
+		getters["https"] = httpGetter
+
+		err = (&getter.Client{
+			Ctx:     ctx,
+			Src:     source.String(),
+			Dst:     directory,
+			Mode:    getter.ClientModeDir,
+			Getters: getters,
+		}).Get()
+		if err == nil || !transport.isRetryable() {
+			return err
+		}
+	}
+	return err
+}
+
+// downloadRetryTransport Remembers whether the last request of a download failed the way AddRetryCondition retries,
+// the errors of go-getter do not tell
+type downloadRetryTransport struct {
+	next http.RoundTripper
+
+	lock      sync.Mutex
+	retryable bool
+}
+
+func (x *downloadRetryTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := x.next.RoundTrip(request)
+	x.setRetryable(err != nil || response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError)
+	if err == nil {
+		response.Body = &downloadRetryBody{ReadCloser: response.Body, transport: x}
+	}
+	return response, err
+}
+
+func (x *downloadRetryTransport) setRetryable(retryable bool) {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	x.retryable = retryable
+}
+
+func (x *downloadRetryTransport) isRetryable() bool {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	return x.retryable
+}
+
+// The connection may also break while the body is read
+type downloadRetryBody struct {
+	io.ReadCloser
+	transport *downloadRetryTransport
+}
+
+func (x *downloadRetryBody) Read(p []byte) (int, error) {
+	n, err := x.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		x.transport.setRetryable(true)
+	}
+	return n, err
+}
+
+type userAgentTransport struct {
+	next http.RoundTripper
+}
+
+func (x *userAgentTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request = request.Clone(request.Context())
+	request.Header.Set("User-Agent", httpUserAgent)
+	return x.next.RoundTrip(request)
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// The client of everything that is not given one, it uses the proxy of the environment and the defaults
+var defaultHttpClient = mustNewHttpClient(&Network{})
+
+func mustNewHttpClient(network *Network) *HttpClient {
+	client, err := NewHttpClient(network)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// SetDefaultHttpClient Replace the client used by everything that is not given one
+func SetDefaultHttpClient(client *HttpClient) {
+	defaultHttpClient = client
+}
+
+// GetDefaultHttpClient The client used by everything that is not given one
+func GetDefaultHttpClient() *HttpClient {
+	return defaultHttpClient
+}
@@ -0,0 +1,202 @@
+package generate_selefra_terraform_provider
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestNetwork_validate(t *testing.T) {
+	assert.Empty(t, (&Network{}).validate())
+
+	problems := (&Network{
+		Proxy:        "://proxy",
+		CaFile:       filepath.Join(t.TempDir(), "missing.pem"),
+		Timeout:      "soon",
+		RetryWait:    "-1s",
+		RetryMaxWait: "30s",
+	}).validate()
+	keys := make([]string, 0)
+	for _, problem := range problems {
+		keys = append(keys, problem.Key)
+	}
+	assert.Equal(t, []string{"network.proxy", "network.ca-file", "network.timeout", "network.retry-wait"}, keys)
+
+	_, err := NewHttpClient(&Network{Timeout: "soon"})
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+}
+
+func TestHttpClient_R(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, httpUserAgent, r.Header.Get("User-Agent"))
+		if requests < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	// 5xx is retried
+	client, err := NewHttpClient(&Network{RetryWait: "1ms", RetryMaxWait: "2ms"})
+	assert.Nil(t, err)
+	response, err := client.R(context.Background()).Get(server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", response.String())
+	assert.Equal(t, 3, requests)
+
+	// Never retried
+	requests = 0
+	client, err = NewHttpClient(&Network{Retries: -1})
+	assert.Nil(t, err)
+	response, err = client.R(context.Background()).Get(server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadGateway, response.StatusCode())
+	assert.Equal(t, 1, requests)
+}
+
+func TestHttpClient_proxy(t *testing.T) {
+	proxied := make([]string, 0)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		_, _ = fmt.Fprint(w, "proxied")
+	}))
+	defer proxy.Close()
+
+	client, err := NewHttpClient(&Network{Proxy: proxy.URL, NoProxy: []string{"internal.example.com"}, Retries: -1})
+	assert.Nil(t, err)
+	response, err := client.R(context.Background()).Get("http://releases.example.com/index.json")
+	assert.Nil(t, err)
+	assert.Equal(t, "proxied", response.String())
+	assert.Equal(t, []string{"http://releases.example.com/index.json"}, proxied)
+
+	// Not sent to the proxy, and the host does not exist
+	_, err = client.R(context.Background()).Get("http://internal.example.com/index.json")
+	assert.NotNil(t, err)
+	assert.Len(t, proxied, 1)
+}
+
+func TestHttpClient_caFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	client, err := NewHttpClient(&Network{Retries: -1})
+	assert.Nil(t, err)
+	_, err = client.R(context.Background()).Get(server.URL)
+	assert.NotNil(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.Nil(t, os.WriteFile(caFile, certificate, os.ModePerm))
+	client, err = NewHttpClient(&Network{CaFile: caFile, Retries: -1})
+	assert.Nil(t, err)
+	response, err := client.R(context.Background()).Get(server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", response.String())
+}
+
+func TestDownloadProviderExecFile(t *testing.T) {
+	archive := bytes.Buffer{}
+	writer := zip.NewWriter(&archive)
+	file, err := writer.Create("terraform-provider-test_v1.0.0")
+	assert.Nil(t, err)
+	_, _ = file.Write([]byte("#!/bin/sh\n"))
+	assert.Nil(t, writer.Close())
+
+	sum := sha256.Sum256(archive.Bytes())
+	sha256Sum := hex.EncodeToString(sum[:])
+
+	requests := 0
+	corrupted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, httpUserAgent, r.Header.Get("User-Agent"))
+		assert.Empty(t, r.URL.Query().Get("checksum"))
+		// go-getter asks for the size with a HEAD first
+		if r.Method == http.MethodGet {
+			requests++
+		}
+		content := archive.Bytes()
+		if corrupted {
+			content = append([]byte{}, content...)
+			content[len(content)/2] ^= 0xff
+		}
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	client, err := NewHttpClient(&Network{Retries: 2, RetryWait: "10ms"})
+	assert.Nil(t, err)
+	directory := t.TempDir()
+	files := []*provider.TerraformProviderFile{
+		{ProviderName: "terraform-provider-test", ProviderVersion: "1.0.0", DownloadUrl: server.URL + "/terraform-provider-test_1.0.0.zip", Sha256Sum: sha256Sum, OS: runtime.GOOS, Arch: runtime.GOARCH},
+	}
+	executable, err := downloadProviderExecFile(context.Background(), client, files, directory)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(directory, "terraform-provider-test", "1.0.0", "terraform-provider-test_v1.0.0"), executable)
+
+	// Already downloaded
+	executable, err = downloadProviderExecFile(context.Background(), client, files, directory)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, executable)
+	assert.Equal(t, 1, requests)
+
+	// A corrupted archive is neither unpacked nor downloaded again
+	corrupted = true
+	requests = 0
+	corruptedDirectory := t.TempDir()
+	executable, err = downloadProviderExecFile(context.Background(), client, files, corruptedDirectory)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Checksums did not match")
+	assert.Empty(t, executable)
+	assert.Equal(t, 1, requests)
+	assert.Empty(t, findProviderExecFile(corruptedDirectory))
+
+	// No file of this platform
+	files[0].OS = "plan9"
+	executable, err = downloadProviderExecFile(context.Background(), client, files, t.TempDir())
+	assert.Nil(t, err)
+	assert.Empty(t, executable)
+}
+
+func TestHttpClient_Download(t *testing.T) {
+	statusCode := http.StatusNotFound
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			requests++
+		}
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	client, err := NewHttpClient(&Network{Retries: 2, RetryWait: "10ms"})
+	assert.Nil(t, err)
+
+	// case 001. a missing file is not retried
+	err = client.Download(context.Background(), server.URL+"/terraform-provider-test_1.0.0.zip", "", t.TempDir())
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, requests)
+
+	// case 002. an unavailable server is
+	statusCode = http.StatusServiceUnavailable
+	requests = 0
+	err = client.Download(context.Background(), server.URL+"/terraform-provider-test_1.0.0.zip", "", t.TempDir())
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, requests)
+}
@@ -0,0 +1,139 @@
+package generate_selefra_terraform_provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/yezihack/colorlog"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logger Where the scaffold reports its progress, the config hands it to everything that runs with it, see Config.SetLogger
+type Logger interface {
+	Debug(format string, args ...any)
+	Info(format string, args ...any)
+	Warn(format string, args ...any)
+	Error(format string, args ...any)
+}
+
+const (
+
+	// LogFormatText Colored lines for humans, this is the default
+	LogFormatText = "text"
+
+	// LogFormatJson One JSON object per line with time, level and msg
+	LogFormatJson = "json"
+)
+
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+var logLevelSlice = []string{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError}
+
+// NewLogger A logger of the given format that drops the messages below the given level
+func NewLogger(writer io.Writer, format, level string) (Logger, error) {
+	levelIndex := -1
+	for index, l := range logLevelSlice {
+		if strings.ToLower(level) == l {
+			levelIndex = index
+		}
+	}
+	if levelIndex < 0 {
+		return nil, fmt.Errorf("unknown log level %s, it must be one of %s", level, strings.Join(logLevelSlice, ", "))
+	}
+
+	switch strings.ToLower(format) {
+	case LogFormatText:
+		// The levels of colorlog are in the same order
+		logger := colorlog.InitWriteLogger(writer, 2, colorlog.DEFAULT_FLAG, true)
+		logger.SetLevel(levelIndex)
+		return logger, nil
+	case LogFormatJson:
+		return &JsonLogger{writer: writer, level: levelIndex}, nil
+	default:
+		return nil, fmt.Errorf("unknown log format %s, it must be %s or %s", format, LogFormatText, LogFormatJson)
+	}
+}
+
+// The logger of everything that is not given one, the messages go to stderr so that stdout is left to the results
+var defaultLogger Logger = colorlog.InitWriteLogger(os.Stderr, 2, colorlog.DEFAULT_FLAG, true)
+
+// SetDefaultLogger Replace the logger used by everything that is not given one
+func SetDefaultLogger(logger Logger) {
+	defaultLogger = logger
+}
+
+// GetDefaultLogger The logger used by everything that is not given one
+func GetDefaultLogger() Logger {
+	return defaultLogger
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// JsonLogger Writes one JSON object per message, safe to use from several goroutines
+type JsonLogger struct {
+	lock   sync.Mutex
+	writer io.Writer
+	level  int
+}
+
+var _ Logger = &JsonLogger{}
+
+func (x *JsonLogger) Debug(format string, args ...any) {
+	x.log(0, format, args...)
+}
+
+func (x *JsonLogger) Info(format string, args ...any) {
+	x.log(1, format, args...)
+}
+
+func (x *JsonLogger) Warn(format string, args ...any) {
+	x.log(2, format, args...)
+}
+
+func (x *JsonLogger) Error(format string, args ...any) {
+	x.log(3, format, args...)
+}
+
+func (x *JsonLogger) log(level int, format string, args ...any) {
+	if level < x.level {
+		return
+	}
+	line, err := json.Marshal(map[string]string{
+		"time":  time.Now().Format(time.RFC3339),
+		"level": logLevelSlice[level],
+		"msg":   fmt.Sprintf(format, args...),
+	})
+	if err != nil {
+		return
+	}
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	_, _ = x.writer.Write(append(line, '\n'))
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// NopLogger Drops every message, for example to keep tests quiet
+type NopLogger struct{}
+
+var _ Logger = &NopLogger{}
+
+func NewNopLogger() *NopLogger {
+	return &NopLogger{}
+}
+
+func (x *NopLogger) Debug(format string, args ...any) {}
+
+func (x *NopLogger) Info(format string, args ...any) {}
+
+func (x *NopLogger) Warn(format string, args ...any) {}
+
+func (x *NopLogger) Error(format string, args ...any) {}
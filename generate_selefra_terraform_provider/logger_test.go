@@ -0,0 +1,55 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger, err := NewLogger(buffer, LogFormatJson, LogLevelWarn)
+	assert.Nil(t, err)
+	logger.Info("not written")
+	logger.Warn("resource %s is skipped", "foo_bar")
+	logger.Error("failed")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	line := make(map[string]string)
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), &line))
+	assert.Equal(t, "warn", line["level"])
+	assert.Equal(t, "resource foo_bar is skipped", line["msg"])
+	assert.NotEmpty(t, line["time"])
+
+	buffer.Reset()
+	logger, err = NewLogger(buffer, LogFormatText, LogLevelError)
+	assert.Nil(t, err)
+	logger.Warn("not written")
+	logger.Error("failed")
+	assert.NotContains(t, buffer.String(), "not written")
+	assert.Contains(t, buffer.String(), "failed")
+
+	_, err = NewLogger(buffer, "yaml", LogLevelInfo)
+	assert.NotNil(t, err)
+	_, err = NewLogger(buffer, LogFormatJson, "verbose")
+	assert.NotNil(t, err)
+}
+
+func TestConfigOptions_Logger(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger, err := NewLogger(buffer, LogFormatJson, LogLevelDebug)
+	assert.Nil(t, err)
+
+	// the config resolvers log through the logger of the options instead of the default one
+	_, err = NewConfigFromOptions(&ConfigOptions{ConfigPath: "not-exists.yml", Logger: logger})
+	assert.NotNil(t, err)
+	assert.Contains(t, buffer.String(), "not-exists.yml")
+
+	config := &Config{}
+	assert.Equal(t, defaultLogger, config.GetLogger())
+	config.SetLogger(NewNopLogger())
+	assert.Equal(t, config.GetLogger(), config.Terraform.TerraformProvider.getLogger())
+}
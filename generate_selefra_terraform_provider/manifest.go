@@ -0,0 +1,351 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// DefaultManifestConcurrency How many providers are generated at the same time unless the manifest says otherwise,
+// every one of them runs its own terraform provider process
+const DefaultManifestConcurrency = 4
+
+// DefaultManifestDownloadCache The directory next to the manifest the providers share their downloads in
+const DefaultManifestDownloadCache = ".selefra-download-cache"
+
+// Manifest Many selefra providers that are generated in one run, see the generate-all command
+type Manifest struct {
+
+	// How many providers are generated at the same time
+	Concurrency int `mapstructure:"concurrency"`
+
+	// The executable files of the terraform providers are downloaded into this directory and shared by all providers
+	DownloadCache string `mapstructure:"download-cache"`
+
+	Providers []*ManifestProvider `mapstructure:"providers"`
+}
+
+// ManifestProvider One provider of the manifest, it takes the same keys as config.yml and may point to a config file
+// that the keys given here override
+type ManifestProvider struct {
+
+	// The name in the report, the short name of the terraform provider if not set
+	Name string `mapstructure:"name"`
+
+	// A configuration file the provider is based on, relative to the manifest
+	ConfigFile string `mapstructure:"config-file"`
+
+	Config `mapstructure:",squash"`
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// NewManifestFromPath Read the manifest and build the configuration of each provider in it, relative paths are resolved
+// against the file that declares them, all problems of all providers are reported at once
+func NewManifestFromPath(manifestPath string) (*Manifest, error) {
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		defaultLogger.Error("read manifest file error: %s", err.Error())
+		return nil, err
+	}
+	problems, err := checkYamlKeys(manifestPath, content, reflect.TypeOf(Manifest{}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrCheckConfigFailed, manifestPath, err.Error())
+	}
+
+	viperConfig := viper.New()
+	viperConfig.SetConfigType("yaml")
+	if err := viperConfig.ReadConfig(bytes.NewReader(content)); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrCheckConfigFailed, manifestPath, err.Error())
+	}
+	manifest := new(Manifest)
+	if err := viperConfig.Unmarshal(manifest); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrCheckConfigFailed, manifestPath, err.Error())
+	}
+
+	manifestDirectory := filepath.Dir(manifestPath)
+	if manifest.Concurrency <= 0 {
+		manifest.Concurrency = DefaultManifestConcurrency
+	}
+	if manifest.DownloadCache == "" {
+		manifest.DownloadCache = DefaultManifestDownloadCache
+	}
+	manifest.DownloadCache = resolvePath(manifestDirectory, manifest.DownloadCache)
+	if len(manifest.Providers) == 0 {
+		problems = append(problems, &ConfigProblem{Key: "providers", Message: "the manifest has no providers"})
+	}
+
+	nameSet := make(map[string]int)
+	outputSet := make(map[string]int)
+	for index, manifestProvider := range manifest.Providers {
+		keyPrefix := fmt.Sprintf("providers[%d].", index)
+		providerProblems, err := manifestProvider.buildConfig(manifestDirectory)
+		if err != nil {
+			return nil, err
+		}
+		for _, problem := range providerProblems {
+			if problem.Location == "" {
+				problem.Key = keyPrefix + problem.Key
+			}
+			problems = append(problems, problem)
+		}
+
+		if otherIndex, exists := nameSet[manifestProvider.Name]; exists {
+			problems = append(problems, &ConfigProblem{Key: keyPrefix + "name", Message: fmt.Sprintf("%s is already the name of providers[%d]", manifestProvider.Name, otherIndex)})
+		}
+		nameSet[manifestProvider.Name] = index
+		// Two providers writing into the same directory would overwrite each other's schema IR and tables
+		if otherIndex, exists := outputSet[manifestProvider.Output.Directory]; exists {
+			problems = append(problems, &ConfigProblem{Key: keyPrefix + "output.directory", Message: fmt.Sprintf("%s is already the output directory of providers[%d]", manifestProvider.Output.Directory, otherIndex)})
+		}
+		outputSet[manifestProvider.Output.Directory] = index
+	}
+
+	if len(problems) != 0 {
+		err := &ConfigValidationError{Problems: problems}
+		defaultLogger.Error(err.Error())
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Merge the config file and the keys of the manifest into the configuration of the provider and check it without the network
+func (x *ManifestProvider) buildConfig(manifestDirectory string) ([]*ConfigProblem, error) {
+	inline := x.Config
+	inline.Output.Directory = resolvePath(manifestDirectory, inline.Output.Directory)
+	inline.Output.TemplatesDirectory = resolvePath(manifestDirectory, inline.Output.TemplatesDirectory)
+
+	config := &Config{}
+	if x.ConfigFile != "" {
+		configFilePath := resolvePath(manifestDirectory, x.ConfigFile)
+		fileConfig, err := readConfigFromPath(configFilePath, defaultLogger)
+		if err != nil {
+			return nil, err
+		}
+		// The config file lives in the checkout of the provider, which is where its output goes unless it says otherwise
+		configFileDirectory := filepath.Dir(configFilePath)
+		if fileConfig.Output.Directory == "" {
+			fileConfig.Output.Directory = configFileDirectory
+		}
+		fileConfig.Output.Directory = resolvePath(configFileDirectory, fileConfig.Output.Directory)
+		fileConfig.Output.TemplatesDirectory = resolvePath(configFileDirectory, fileConfig.Output.TemplatesDirectory)
+		config = fileConfig
+	}
+	config.merge(&inline)
+
+	if x.Name == "" {
+		x.Name = config.Terraform.TerraformProvider.ParseProviderShortName()
+	}
+	if config.Output.Directory == "" {
+		config.Output.Directory = resolvePath(manifestDirectory, x.Name)
+	}
+	config.Output.Directory = filepath.Clean(config.Output.Directory)
+
+	// The module name is only detected from the output directory, the working directory and the environment variables
+	// would give every provider the same one
+	if config.Selefra.ModuleName == "" {
+		config.Selefra.ModuleName = readModuleNameFromGoMod(filepath.Join(config.Output.Directory, "go.mod"), defaultLogger)
+	}
+	if config.Selefra.ModuleName == "" {
+		config.Selefra.ModuleName = config.tryFindGitModuleNameFromLocalGitRepo()
+	}
+	if config.Selefra.ModuleName == "" {
+		config.keyProblems = append(config.keyProblems, &ConfigProblem{
+			Key:     "selefra.module-name",
+			Message: "The module name cannot be read from go.mod or the git repository in the output directory, please set it",
+		})
+	}
+
+	// The module name is only read from the output directory above, never from the working directory
+	x.Config = *config
+	return validateConfig(&x.Config, configValidationOptions{skipModuleName: true}), nil
+}
+
+// Relative paths are resolved against the given directory, empty stays empty
+func resolvePath(directory, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(directory, path)
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// ManifestRunner Generates every provider of the manifest, a failing provider does not stop the others
+type ManifestRunner struct {
+	manifest *Manifest
+	logger   Logger
+}
+
+func NewManifestRunner(manifest *Manifest) *ManifestRunner {
+	return &ManifestRunner{
+		manifest: manifest,
+		logger:   defaultLogger,
+	}
+}
+
+// SetLogger Log the run and every provider of it through the given logger
+func (x *ManifestRunner) SetLogger(logger Logger) *ManifestRunner {
+	x.logger = logger
+	return x
+}
+
+func (x *ManifestRunner) Run(ctx context.Context) *RunReport {
+	report := &RunReport{
+		Results: make([]*ProviderRunResult, len(x.manifest.Providers)),
+	}
+	x.logger.Info("begin generate %d providers, %d at a time, downloads are shared in %s", len(x.manifest.Providers), x.manifest.Concurrency, x.manifest.DownloadCache)
+
+	semaphore := make(chan struct{}, x.manifest.Concurrency)
+	wg := sync.WaitGroup{}
+	for index, manifestProvider := range x.manifest.Providers {
+		wg.Add(1)
+		go func(index int, manifestProvider *ManifestProvider) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() {
+				<-semaphore
+			}()
+			report.Results[index] = x.runProvider(ctx, manifestProvider)
+		}(index, manifestProvider)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (x *ManifestRunner) runProvider(ctx context.Context, manifestProvider *ManifestProvider) *ProviderRunResult {
+	startTime := time.Now()
+	result := &ProviderRunResult{
+		Name:            manifestProvider.Name,
+		OutputDirectory: manifestProvider.Output.Directory,
+	}
+	defer func() {
+		result.Duration = time.Since(startTime)
+	}()
+	if ctx.Err() != nil {
+		result.Err = ctx.Err()
+		return result
+	}
+
+	x.logger.Info("begin generate provider %s into %s", manifestProvider.Name, manifestProvider.Output.Directory)
+	config := &manifestProvider.Config
+	config.SetDownloadDirectory(x.manifest.DownloadCache).SetLogger(x.logger)
+	if err := checkConfig(config); err != nil {
+		result.Err = err
+		return result
+	}
+	generator := NewGenerator(config)
+	result.Err = generator.Run()
+	if summary := generator.GetSummary(); summary != nil {
+		result.Resources = summary.Resources
+		result.Tables = summary.Tables
+		result.Skipped = summary.Skipped
+	}
+	if result.Err != nil {
+		x.logger.Error("generate provider %s failed: %s", manifestProvider.Name, result.Err.Error())
+	} else {
+		x.logger.Info("generate provider %s success", manifestProvider.Name)
+	}
+	return result
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// RunReport What happened to each provider of the manifest, in the order of the manifest
+type RunReport struct {
+	Results []*ProviderRunResult `json:"results"`
+}
+
+type ProviderRunResult struct {
+	Name            string `json:"name"`
+	OutputDirectory string `json:"output_directory"`
+
+	// The terraform resources in the schema IR
+	Resources int `json:"resources"`
+
+	// The tables that were rendered
+	Tables int `json:"tables"`
+
+	// The resources that no table was rendered for
+	Skipped []*SkippedResource `json:"skipped"`
+
+	Duration time.Duration `json:"-"`
+
+	Err error `json:"-"`
+}
+
+// MarshalJSON The duration in seconds and the error as its message
+func (x *ProviderRunResult) MarshalJSON() ([]byte, error) {
+	type alias ProviderRunResult
+	errorMessage := ""
+	if x.Err != nil {
+		errorMessage = x.Err.Error()
+	}
+	return json.Marshal(&struct {
+		*alias
+		DurationSeconds float64 `json:"duration_seconds"`
+		Error           string  `json:"error,omitempty"`
+	}{
+		alias:           (*alias)(x),
+		DurationSeconds: x.Duration.Seconds(),
+		Error:           errorMessage,
+	})
+}
+
+// Err Nil if every provider was generated, otherwise the error of the first provider that failed
+func (x *RunReport) Err() error {
+	failedCount := 0
+	var firstErr error
+	for _, result := range x.Results {
+		if result.Err == nil {
+			continue
+		}
+		failedCount++
+		if firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", result.Name, result.Err)
+		}
+	}
+	if failedCount == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d providers failed, the first one: %w", failedCount, len(x.Results), firstErr)
+}
+
+// WriteTable One line per provider, the skipped resources and the errors are listed below the table
+func (x *RunReport) WriteTable(writer io.Writer) error {
+	tableWriter := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tableWriter, "PROVIDER\tRESOURCES\tTABLES\tSKIPPED\tDURATION\tRESULT")
+	for _, result := range x.Results {
+		status := "ok"
+		if result.Err != nil {
+			status = "failed"
+		}
+		_, _ = fmt.Fprintf(tableWriter, "%s\t%d\t%d\t%d\t%s\t%s\n", result.Name, result.Resources, result.Tables, len(result.Skipped), result.Duration.Round(time.Second), status)
+	}
+	if err := tableWriter.Flush(); err != nil {
+		return err
+	}
+
+	for _, result := range x.Results {
+		if len(result.Skipped) != 0 {
+			_, _ = fmt.Fprintf(writer, "\n%s skipped:\n", result.Name)
+			for _, skipped := range result.Skipped {
+				_, _ = fmt.Fprintf(writer, "\t%s: %s\n", skipped.ResourceName, skipped.Reason)
+			}
+		}
+		if result.Err != nil {
+			_, _ = fmt.Fprintf(writer, "\n%s error: %s\n", result.Name, result.Err.Error())
+		}
+	}
+	return nil
+}
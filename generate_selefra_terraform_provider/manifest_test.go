@@ -0,0 +1,107 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewManifestFromPath(t *testing.T) {
+	workspace := t.TempDir()
+
+	// a checkout with its own config file and go.mod
+	checkout := filepath.Join(workspace, "selefra-provider-bar")
+	assert.Nil(t, os.MkdirAll(checkout, 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(checkout, "go.mod"), []byte("module github.com/selefra/selefra-provider-bar\n\ngo 1.19\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(checkout, "config.yml"), []byte(`terraform:
+  provider:
+    repo-url: https://github.com/bar/terraform-provider-bar
+    version: 1.0.0
+`), 0644))
+
+	manifestPath := filepath.Join(workspace, "providers.yaml")
+	assert.Nil(t, os.WriteFile(manifestPath, []byte(`concurrency: 2
+providers:
+  - terraform:
+      provider:
+        repo-url: https://github.com/foo/terraform-provider-foo
+    selefra:
+      module-name: github.com/selefra/selefra-provider-foo
+  - name: bar
+    config-file: selefra-provider-bar/config.yml
+    terraform:
+      provider:
+        version: 2.0.0
+`), 0644))
+
+	manifest, err := NewManifestFromPath(manifestPath)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, manifest.Concurrency)
+	assert.Equal(t, filepath.Join(workspace, DefaultManifestDownloadCache), manifest.DownloadCache)
+	assert.Equal(t, 2, len(manifest.Providers))
+
+	foo := manifest.Providers[0]
+	assert.Equal(t, "foo", foo.Name)
+	assert.Equal(t, filepath.Join(workspace, "foo"), foo.Output.Directory)
+
+	// the manifest overrides the config file, the output and module name come from the checkout
+	bar := manifest.Providers[1]
+	assert.Equal(t, "2.0.0", bar.Terraform.TerraformProvider.Version)
+	assert.Equal(t, checkout, bar.Output.Directory)
+	assert.Equal(t, "github.com/selefra/selefra-provider-bar", bar.Selefra.ModuleName)
+
+	// everything wrong with the manifest is reported at once
+	assert.Nil(t, os.WriteFile(manifestPath, []byte(`concurency: 2
+providers:
+  - name: foo
+    terraform:
+      provider:
+        repo-url: https://github.com/foo/terraform-provider-foo
+    selefra:
+      module-name: github.com/selefra/selefra-provider-foo
+  - name: foo
+    terraform:
+      provider:
+        repo-url: https://github.com/foo/terraform-provider-foo
+`), 0644))
+	_, err = NewManifestFromPath(manifestPath)
+	validationError := &ConfigValidationError{}
+	assert.True(t, errors.As(err, &validationError))
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+	assert.Contains(t, err.Error(), "concurency: unknown key, did you mean concurrency?")
+	assert.Contains(t, err.Error(), "providers[1].name: foo is already the name of providers[0]")
+	assert.Contains(t, err.Error(), "providers[1].output.directory")
+	assert.Contains(t, err.Error(), "providers[1].selefra.module-name")
+}
+
+func TestRunReport(t *testing.T) {
+	report := &RunReport{
+		Results: []*ProviderRunResult{
+			{Name: "foo", Resources: 3, Tables: 2, Skipped: []*SkippedResource{{ResourceName: "foo_no_id", Reason: skippedReasonNoIdColumn}}},
+			{Name: "bar", Err: ErrDownload},
+		},
+	}
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, report.WriteTable(buffer))
+	assert.Contains(t, buffer.String(), "PROVIDER")
+	assert.Contains(t, buffer.String(), "foo_no_id: "+skippedReasonNoIdColumn)
+	assert.Contains(t, buffer.String(), "bar error: "+ErrDownload.Error())
+
+	err := report.Err()
+	assert.ErrorIs(t, err, ErrDownload)
+	assert.Contains(t, err.Error(), "1 of 2 providers failed")
+	assert.Equal(t, ExitCodeDownload, ExitCode(err))
+
+	// the error is kept as its message and the duration in seconds
+	reportBytes, err := json.Marshal(report)
+	assert.Nil(t, err)
+	assert.Contains(t, string(reportBytes), `"error":"`+ErrDownload.Error()+`"`)
+	assert.Contains(t, string(reportBytes), `"duration_seconds":0`)
+
+	report.Results = report.Results[:1]
+	assert.Nil(t, report.Err())
+}
@@ -0,0 +1,185 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Prompter Asks questions on the terminal, when assumeYes is set every question is answered with its default
+type Prompter struct {
+	reader    *bufio.Reader
+	writer    io.Writer
+	assumeYes bool
+}
+
+func NewPrompter(reader io.Reader, writer io.Writer, assumeYes bool) *Prompter {
+	return &Prompter{
+		reader:    bufio.NewReader(reader),
+		writer:    writer,
+		assumeYes: assumeYes,
+	}
+}
+
+// Ask A free text question, an empty answer is the default
+func (x *Prompter) Ask(question, defaultValue string) (string, error) {
+	if x.assumeYes {
+		return defaultValue, nil
+	}
+	if defaultValue != "" {
+		_, _ = fmt.Fprintf(x.writer, "%s [%s]: ", question, defaultValue)
+	} else {
+		_, _ = fmt.Fprintf(x.writer, "%s: ", question)
+	}
+	answer, err := x.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+// Confirm A yes or no question
+func (x *Prompter) Confirm(question string, defaultValue bool) (bool, error) {
+	defaultAnswer := "y/N"
+	if defaultValue {
+		defaultAnswer = "Y/n"
+	}
+	for {
+		answer, err := x.Ask(question+" ("+defaultAnswer+")", "")
+		if err != nil || answer == "" {
+			return defaultValue, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		_, _ = fmt.Fprintln(x.writer, "please answer y or n")
+	}
+}
+
+// Choose Pick one of the choices by its number, returns its index
+func (x *Prompter) Choose(question string, choices []string, defaultIndex int) (int, error) {
+	if x.assumeYes {
+		return defaultIndex, nil
+	}
+	for index, choice := range choices {
+		_, _ = fmt.Fprintf(x.writer, "  %d) %s\n", index+1, choice)
+	}
+	for {
+		answer, err := x.Ask(question, strconv.Itoa(defaultIndex+1))
+		if err != nil {
+			return 0, err
+		}
+		number, err := strconv.Atoi(answer)
+		if err == nil && number >= 1 && number <= len(choices) {
+			return number - 1, nil
+		}
+		_, _ = fmt.Fprintf(x.writer, "please enter a number between 1 and %d\n", len(choices))
+	}
+}
+
+// MultiSelect Pick any number of the items, an empty answer selects all of them, see ParseSelection for the syntax
+func (x *Prompter) MultiSelect(question string, items []string) ([]string, error) {
+	if x.assumeYes {
+		return items, nil
+	}
+	_, _ = fmt.Fprintf(x.writer, "%d items, answer with numbers (3), ranges (3-7), names or patterns (aws_s3_*) separated by commas, ? lists them, empty selects all\n", len(items))
+	for {
+		answer, err := x.Ask(question, "")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			return items, nil
+		}
+		if answer == "?" {
+			for index, item := range items {
+				_, _ = fmt.Fprintf(x.writer, "  %d) %s\n", index+1, item)
+			}
+			continue
+		}
+		selected, err := ParseSelection(answer, items)
+		if err == nil {
+			_, _ = fmt.Fprintf(x.writer, "%d selected\n", len(selected))
+			return selected, nil
+		}
+		_, _ = fmt.Fprintln(x.writer, err.Error())
+	}
+}
+
+func (x *Prompter) readLine() (string, error) {
+	line, err := x.reader.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("read answer error: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ParseSelection Comma separated numbers starting at 1, ranges like 3-7, item names and patterns like aws_s3_*,
+// the selected items are returned in the order of the items
+func ParseSelection(answer string, items []string) ([]string, error) {
+	selectedSet := make(map[int]struct{})
+	for _, token := range strings.Split(answer, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		matchCount := 0
+		if from, to, isRange := parseSelectionRange(token); isRange {
+			if from < 1 || to > len(items) || from > to {
+				return nil, fmt.Errorf("range %s is out of 1-%d", token, len(items))
+			}
+			for number := from; number <= to; number++ {
+				selectedSet[number-1] = struct{}{}
+			}
+			continue
+		}
+		if number, err := strconv.Atoi(token); err == nil {
+			if number < 1 || number > len(items) {
+				return nil, fmt.Errorf("number %d is out of 1-%d", number, len(items))
+			}
+			selectedSet[number-1] = struct{}{}
+			continue
+		}
+		for index, item := range items {
+			if matched, err := filepath.Match(token, item); err != nil {
+				return nil, fmt.Errorf("bad pattern %s: %s", token, err.Error())
+			} else if matched {
+				selectedSet[index] = struct{}{}
+				matchCount++
+			}
+		}
+		if matchCount == 0 {
+			return nil, fmt.Errorf("%s matches nothing", token)
+		}
+	}
+	selected := make([]string, 0, len(selectedSet))
+	for index, item := range items {
+		if _, exists := selectedSet[index]; exists {
+			selected = append(selected, item)
+		}
+	}
+	return selected, nil
+}
+
+func parseSelectionRange(token string) (from, to int, isRange bool) {
+	split := strings.SplitN(token, "-", 2)
+	if len(split) != 2 {
+		return 0, 0, false
+	}
+	from, fromErr := strconv.Atoi(strings.TrimSpace(split[0]))
+	to, toErr := strconv.Atoi(strings.TrimSpace(split[1]))
+	if fromErr != nil || toErr != nil {
+		return 0, 0, false
+	}
+	return from, to, true
+}
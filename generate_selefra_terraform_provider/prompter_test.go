@@ -0,0 +1,67 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestParseSelection(t *testing.T) {
+	items := []string{"aws_instance", "aws_s3_bucket", "aws_s3_bucket_policy", "aws_vpc", "aws_subnet"}
+
+	selected, err := ParseSelection("4, 1", items)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"aws_instance", "aws_vpc"}, selected)
+
+	selected, err = ParseSelection("2-3,aws_subnet", items)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"aws_s3_bucket", "aws_s3_bucket_policy", "aws_subnet"}, selected)
+
+	// the same item selected twice is returned once
+	selected, err = ParseSelection("aws_s3_*,2", items)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"aws_s3_bucket", "aws_s3_bucket_policy"}, selected)
+
+	_, err = ParseSelection("6", items)
+	assert.NotNil(t, err)
+	_, err = ParseSelection("3-1", items)
+	assert.NotNil(t, err)
+	_, err = ParseSelection("gcp_*", items)
+	assert.NotNil(t, err)
+}
+
+func TestPrompter(t *testing.T) {
+	output := &bytes.Buffer{}
+	prompter := NewPrompter(strings.NewReader("\nfoo\n7\n2\nmaybe\ny\nnope_*\n1-2\n"), output, false)
+
+	answer, err := prompter.Ask("module", "default")
+	assert.Nil(t, err)
+	assert.Equal(t, "default", answer)
+	answer, err = prompter.Ask("module", "default")
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", answer)
+
+	// an answer out of range is asked again
+	index, err := prompter.Choose("provider", []string{"a", "b"}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, index)
+
+	ok, err := prompter.Confirm("overwrite?", false)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	selected, err := prompter.MultiSelect("resources", []string{"a", "b", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, selected)
+	assert.Contains(t, output.String(), "nope_* matches nothing")
+
+	// nothing is read with --yes
+	prompter = NewPrompter(strings.NewReader(""), output, true)
+	answer, err = prompter.Ask("module", "default")
+	assert.Nil(t, err)
+	assert.Equal(t, "default", answer)
+	selected, err = prompter.MultiSelect("resources", []string{"a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, selected)
+}
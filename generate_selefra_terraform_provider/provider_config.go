@@ -0,0 +1,173 @@
+package generate_selefra_terraform_provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// terraform.provider.config is either a YAML mapping or, for compatibility, a string holding a JSON object:
+//
+//	config:
+//	  region: ${env:AWS_REGION}
+//	  access_key: ${file:/run/secrets/aws_access_key}
+//
+// The references are only resolved right before the provider is started, so the configuration, the cached one included,
+// only ever holds the references and never the values they resolve to
+
+// ${env:AWS_REGION} or ${file:/run/secrets/aws_access_key}, $${ escapes a literal ${
+var providerConfigReferenceRegex = regexp.MustCompile(`\$?\$\{([a-zA-Z]+):([^}]*)\}`)
+
+// The sources a reference can read its value from
+const (
+	ProviderConfigReferenceEnv  = "env"
+	ProviderConfigReferenceFile = "file"
+)
+
+// ParseProviderConfig The raw provider configuration as a mapping, with the references not resolved yet
+func (x *TerraformProvider) ParseProviderConfig() (map[string]any, error) {
+	switch config := x.Config.(type) {
+	case nil:
+		return make(map[string]any), nil
+	case string:
+		providerConfig := make(map[string]any)
+		if strings.TrimSpace(config) == "" {
+			return providerConfig, nil
+		}
+		if err := json.Unmarshal([]byte(config), &providerConfig); err != nil {
+			return nil, fmt.Errorf("must be a YAML mapping or a string holding a JSON object: %s", describeJsonError(config, err))
+		}
+		return providerConfig, nil
+	case map[string]any:
+		return config, nil
+	default:
+		return nil, fmt.Errorf("must be a YAML mapping or a string holding a JSON object, got %T", config)
+	}
+}
+
+// ResolveProviderConfig The provider configuration the provider is started with, with every reference replaced by its value
+func (x *TerraformProvider) ResolveProviderConfig() (map[string]any, error) {
+	providerConfig, err := x.ParseProviderConfig()
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolveProviderConfigValue(providerConfig, "")
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]any), nil
+}
+
+// The references in the provider configuration with a source that does not exist, they can be found without resolving them
+func (x *TerraformProvider) checkProviderConfigReferences() []string {
+	providerConfig, err := x.ParseProviderConfig()
+	if err != nil {
+		return []string{err.Error()}
+	}
+	problems := make([]string, 0)
+	walkProviderConfigStrings(providerConfig, "", func(keyPath, value string) {
+		for _, match := range providerConfigReferenceRegex.FindAllStringSubmatch(value, -1) {
+			if strings.HasPrefix(match[0], "$$") {
+				continue
+			}
+			if match[1] != ProviderConfigReferenceEnv && match[1] != ProviderConfigReferenceFile {
+				problems = append(problems, fmt.Sprintf("%s: unknown reference %s, it must be ${env:NAME} or ${file:PATH}", keyPath, match[0]))
+			} else if strings.TrimSpace(match[2]) == "" {
+				problems = append(problems, fmt.Sprintf("%s: reference %s is empty", keyPath, match[0]))
+			}
+		}
+	})
+	sort.Strings(problems)
+	return problems
+}
+
+func resolveProviderConfigValue(value any, keyPath string) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		resolved := make(map[string]any, len(v))
+		for key, item := range v {
+			resolvedItem, err := resolveProviderConfigValue(item, joinConfigKey(keyPath, key))
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = resolvedItem
+		}
+		return resolved, nil
+	case []any:
+		resolved := make([]any, len(v))
+		for index, item := range v {
+			resolvedItem, err := resolveProviderConfigValue(item, fmt.Sprintf("%s[%d]", keyPath, index))
+			if err != nil {
+				return nil, err
+			}
+			resolved[index] = resolvedItem
+		}
+		return resolved, nil
+	case string:
+		return resolveProviderConfigString(v, keyPath)
+	default:
+		return value, nil
+	}
+}
+
+func resolveProviderConfigString(value, keyPath string) (string, error) {
+	var resolveErr error
+	resolved := providerConfigReferenceRegex.ReplaceAllStringFunc(value, func(reference string) string {
+		if strings.HasPrefix(reference, "$$") {
+			return reference[1:]
+		}
+		match := providerConfigReferenceRegex.FindStringSubmatch(reference)
+		switch match[1] {
+		case ProviderConfigReferenceEnv:
+			envValue, exists := os.LookupEnv(match[2])
+			if !exists && resolveErr == nil {
+				resolveErr = fmt.Errorf("%s: environment variable %s referenced by %s is not set", keyPath, match[2], reference)
+			}
+			return envValue
+		case ProviderConfigReferenceFile:
+			fileBytes, err := os.ReadFile(match[2])
+			if err != nil && resolveErr == nil {
+				resolveErr = fmt.Errorf("%s: read file referenced by %s error: %s", keyPath, reference, err.Error())
+			}
+			// Secrets files usually end with a new line that is not part of the secret
+			return strings.TrimRight(string(fileBytes), "\r\n")
+		default:
+			if resolveErr == nil {
+				resolveErr = fmt.Errorf("%s: unknown reference %s", keyPath, reference)
+			}
+			return reference
+		}
+	})
+	return resolved, resolveErr
+}
+
+func walkProviderConfigStrings(value any, keyPath string, visit func(keyPath, value string)) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			walkProviderConfigStrings(item, joinConfigKey(keyPath, key), visit)
+		}
+	case []any:
+		for index, item := range v {
+			walkProviderConfigStrings(item, fmt.Sprintf("%s[%d]", keyPath, index), visit)
+		}
+	case string:
+		visit(keyPath, v)
+	}
+}
+
+func isProviderConfigEmpty(config any) bool {
+	switch v := config.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case map[string]any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
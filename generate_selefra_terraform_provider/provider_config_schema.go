@@ -0,0 +1,230 @@
+package generate_selefra_terraform_provider
+
+import (
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"sort"
+	"strings"
+)
+
+// The types of the arguments of the provider block, the ones a selefra provider configuration can be checked against
+const (
+	ProviderConfigTypeBool   = "bool"
+	ProviderConfigTypeInt    = "int"
+	ProviderConfigTypeFloat  = "float"
+	ProviderConfigTypeString = "string"
+	ProviderConfigTypeList   = "list"
+	ProviderConfigTypeSet    = "set"
+	ProviderConfigTypeMap    = "map"
+)
+
+// TerraformProviderConfigArgumentIR An argument of the provider block of the terraform provider, the configuration of
+// the generated selefra provider is handed to the terraform provider as that block
+type TerraformProviderConfigArgumentIR struct {
+	Name string `json:"name"`
+
+	// One of the ProviderConfigType* constants
+	Type string `json:"type"`
+
+	// The type of the elements of a list, set or map of primitive values, empty for a block
+	ElemType string `json:"elem_type,omitempty"`
+
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Sensitive   bool   `json:"sensitive,omitempty"`
+
+	// The terraform provider has a default for it, usually from an environment variable such as AWS_REGION, so a
+	// required argument may be left out. Only a provider in the same process reports it, the ones started over gRPC are
+	// listed in terraform.provider.config-defaults
+	HasDefault bool `json:"has_default,omitempty"`
+
+	// The deprecation message, empty if it is not deprecated
+	Deprecated string `json:"deprecated,omitempty"`
+
+	// The arguments of a nested block, a list or a set of them, a block with max items 1 may be written as one mapping
+	Arguments []*TerraformProviderConfigArgumentIR `json:"arguments,omitempty"`
+	MaxItems  int                                  `json:"max_items,omitempty"`
+}
+
+// FromTerraformProviderConfigSchema The arguments of the provider block, the required ones first and then by name
+func FromTerraformProviderConfigSchema(schemaMap shim.SchemaMap) []*TerraformProviderConfigArgumentIR {
+	arguments := make([]*TerraformProviderConfigArgumentIR, 0)
+	if schemaMap == nil {
+		return arguments
+	}
+	schemaMap.Range(func(name string, argumentSchema shim.Schema) bool {
+		// Computed only, it can not be set
+		if !argumentSchema.Required() && !argumentSchema.Optional() {
+			return true
+		}
+		arguments = append(arguments, FromTerraformProviderConfigArgument(name, argumentSchema))
+		return true
+	})
+	sort.SliceStable(arguments, func(i, j int) bool {
+		if arguments[i].Required != arguments[j].Required {
+			return arguments[i].Required
+		}
+		return arguments[i].Name < arguments[j].Name
+	})
+	return arguments
+}
+
+func FromTerraformProviderConfigArgument(name string, argumentSchema shim.Schema) *TerraformProviderConfigArgumentIR {
+	argument := &TerraformProviderConfigArgumentIR{
+		Name:        name,
+		Type:        toProviderConfigType(argumentSchema.Type()),
+		Description: strings.TrimSpace(argumentSchema.Description()),
+		Required:    argumentSchema.Required(),
+		Sensitive:   argumentSchema.Sensitive(),
+		Deprecated:  argumentSchema.Deprecated(),
+		MaxItems:    argumentSchema.MaxItems(),
+	}
+	// A default function may return nothing here and still have a value where the provider runs. The schema of a
+	// provider started over gRPC has neither
+	defaultValue, err := argumentSchema.DefaultValue()
+	argument.HasDefault = argumentSchema.DefaultFunc() != nil || (err == nil && defaultValue != nil)
+	switch elem := argumentSchema.Elem().(type) {
+	case shim.Resource:
+		argument.Arguments = FromTerraformProviderConfigSchema(elem.Schema())
+	case shim.Schema:
+		argument.ElemType = toProviderConfigType(elem.Type())
+	}
+	return argument
+}
+
+// Mark the top level arguments of terraform.provider.config-defaults as having a default, and return the names that
+// are not an argument of the provider block
+func applyProviderConfigDefaults(arguments []*TerraformProviderConfigArgumentIR, configDefaults []string) []string {
+	unknown := make([]string, 0)
+	for _, name := range configDefaults {
+		found := false
+		for _, argument := range arguments {
+			if argument.Name == name {
+				argument.HasDefault = true
+				found = true
+			}
+		}
+		if !found {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+func toProviderConfigType(valueType shim.ValueType) string {
+	switch valueType {
+	case shim.TypeBool:
+		return ProviderConfigTypeBool
+	case shim.TypeInt:
+		return ProviderConfigTypeInt
+	case shim.TypeFloat:
+		return ProviderConfigTypeFloat
+	case shim.TypeList:
+		return ProviderConfigTypeList
+	case shim.TypeSet:
+		return ProviderConfigTypeSet
+	case shim.TypeMap:
+		return ProviderConfigTypeMap
+	default:
+		return ProviderConfigTypeString
+	}
+}
+
+// IsBlock Whether the argument is a nested block rather than a value
+func (x *TerraformProviderConfigArgumentIR) IsBlock() bool {
+	return len(x.Arguments) != 0
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// BuildProviderConfigTemplate The configuration the generated selefra provider suggests for itself, every argument
+// commented out, with its description and whether it is required or sensitive. Removing the first # of every line
+// leaves the descriptions as comments:
+//
+//	##  Required. The region where AWS operations will take place.
+//	#region: ""
+//	##  Optional, Sensitive. AWS access key.
+//	#access_key: ""
+func BuildProviderConfigTemplate(arguments []*TerraformProviderConfigArgumentIR) string {
+	buff := strings.Builder{}
+	writeProviderConfigArguments(&buff, arguments, "")
+	return buff.String()
+}
+
+func writeProviderConfigArguments(buff *strings.Builder, arguments []*TerraformProviderConfigArgumentIR, indent string) {
+	for _, argument := range arguments {
+		buff.WriteString("#" + indent + "#  " + describeProviderConfigArgument(argument) + "\n")
+		switch {
+		case argument.IsBlock() && argument.MaxItems == 1:
+			buff.WriteString("#" + indent + argument.Name + ":\n")
+			writeProviderConfigArguments(buff, argument.Arguments, indent+"  ")
+		case argument.IsBlock():
+			buff.WriteString("#" + indent + argument.Name + ":\n")
+			buff.WriteString("#" + indent + "  -\n")
+			writeProviderConfigArguments(buff, argument.Arguments, indent+"    ")
+		default:
+			buff.WriteString("#" + indent + argument.Name + ": " + providerConfigPlaceholder(argument) + "\n")
+		}
+	}
+}
+
+// Required, Sensitive. The description of the argument
+func describeProviderConfigArgument(argument *TerraformProviderConfigArgumentIR) string {
+	markers := make([]string, 0)
+	switch {
+	case argument.Required && argument.HasDefault:
+		markers = append(markers, "Required, defaults from the environment")
+	case argument.Required:
+		markers = append(markers, "Required")
+	default:
+		markers = append(markers, "Optional")
+	}
+	if argument.Sensitive {
+		markers = append(markers, "Sensitive")
+	}
+	if argument.Deprecated != "" {
+		markers = append(markers, "Deprecated")
+	}
+	description := strings.Join(markers, ", ") + "."
+	// The description of a provider may span several lines, the template has one line per argument
+	if text := strings.Join(strings.Fields(argument.Description), " "); text != "" {
+		description += " " + text
+	}
+	if argument.Deprecated != "" {
+		description += " " + strings.Join(strings.Fields(argument.Deprecated), " ")
+	}
+	return description
+}
+
+// The empty value of the type of the argument
+func providerConfigPlaceholder(argument *TerraformProviderConfigArgumentIR) string {
+	switch argument.Type {
+	case ProviderConfigTypeBool:
+		return "false"
+	case ProviderConfigTypeInt:
+		return "0"
+	case ProviderConfigTypeFloat:
+		return "0.0"
+	case ProviderConfigTypeList, ProviderConfigTypeSet:
+		return "[]"
+	case ProviderConfigTypeMap:
+		return "{}"
+	default:
+		return `""`
+	}
+}
+
+// BuildClientsConfigTemplate The clients section of the configuration of a provider that starts several clients, every
+// entry is started with the arguments above overlaid with its own config
+func BuildClientsConfigTemplate() string {
+	return `##  Optional. One client for every entry, the rows each one pulls get its account_id and region as columns.
+##  Without clients the provider starts one client with the arguments above.
+#clients:
+#  -
+#    ##  Optional. The account the client pulls the resources of.
+#    account_id: ""
+#    ##  Optional. The region the client pulls the resources in, region of the arguments if not set.
+#    region: ""
+#    ##  Optional. The arguments of this client, overlaid on the arguments above.
+#    config: {}
+`
+}
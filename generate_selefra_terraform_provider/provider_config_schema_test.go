@@ -0,0 +1,119 @@
+package generate_selefra_terraform_provider
+
+import (
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"testing"
+)
+
+func newTestProviderConfigSchema() shim.SchemaMap {
+	return shimschema.SchemaMap{
+		"region":      (&shimschema.Schema{Type: shim.TypeString, Required: true, Description: "The region\nof the resources."}).Shim(),
+		"access_key":  (&shimschema.Schema{Type: shim.TypeString, Optional: true, Sensitive: true}).Shim(),
+		"max_retries": (&shimschema.Schema{Type: shim.TypeInt, Optional: true, Deprecated: "use retry_mode"}).Shim(),
+		"account_id":  (&shimschema.Schema{Type: shim.TypeString, Computed: true}).Shim(),
+		"allowed_account_ids": (&shimschema.Schema{
+			Type:     shim.TypeSet,
+			Optional: true,
+			Elem:     (&shimschema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+		"assume_role": (&shimschema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: (&shimschema.Resource{Schema: shimschema.SchemaMap{
+				"role_arn": (&shimschema.Schema{Type: shim.TypeString, Required: true}).Shim(),
+			}}).Shim(),
+		}).Shim(),
+		"endpoints": (&shimschema.Schema{
+			Type:     shim.TypeSet,
+			Optional: true,
+			Elem: (&shimschema.Resource{Schema: shimschema.SchemaMap{
+				"s3": (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+			}}).Shim(),
+		}).Shim(),
+	}
+}
+
+func TestFromTerraformProviderConfigSchema(t *testing.T) {
+	arguments := FromTerraformProviderConfigSchema(newTestProviderConfigSchema())
+	names := make([]string, 0)
+	for _, argument := range arguments {
+		names = append(names, argument.Name)
+	}
+	// Required first, the computed account_id can not be set
+	assert.Equal(t, []string{"region", "access_key", "allowed_account_ids", "assume_role", "endpoints", "max_retries"}, names)
+	assert.Equal(t, &TerraformProviderConfigArgumentIR{Name: "region", Type: ProviderConfigTypeString, Description: "The region\nof the resources.", Required: true}, arguments[0])
+	assert.True(t, arguments[1].Sensitive)
+	assert.Equal(t, ProviderConfigTypeString, arguments[2].ElemType)
+	assert.True(t, arguments[3].IsBlock())
+	assert.Equal(t, 1, arguments[3].MaxItems)
+	assert.Equal(t, "role_arn", arguments[3].Arguments[0].Name)
+
+	assert.Empty(t, FromTerraformProviderConfigSchema(nil))
+}
+
+func TestFromTerraformProviderConfigArgument_HasDefault(t *testing.T) {
+	// The default function returns nothing while the environment variable is not set, as the one of AWS_REGION does
+	region := FromTerraformProviderConfigArgument("region", (&shimschema.Schema{
+		Type:     shim.TypeString,
+		Required: true,
+		DefaultFunc: func() (interface{}, error) {
+			return nil, nil
+		},
+	}).Shim())
+	assert.True(t, region.Required)
+	assert.True(t, region.HasDefault)
+	assert.Equal(t, "Required, defaults from the environment.", describeProviderConfigArgument(region))
+
+	maxRetries := FromTerraformProviderConfigArgument("max_retries", (&shimschema.Schema{Type: shim.TypeInt, Optional: true, Default: 25}).Shim())
+	assert.True(t, maxRetries.HasDefault)
+	assert.Equal(t, "Optional.", describeProviderConfigArgument(maxRetries))
+
+	assert.False(t, FromTerraformProviderConfigArgument("profile", (&shimschema.Schema{Type: shim.TypeString, Required: true}).Shim()).HasDefault)
+}
+
+func Test_applyProviderConfigDefaults(t *testing.T) {
+	arguments := FromTerraformProviderConfigSchema(newTestProviderConfigSchema())
+	assert.Equal(t, []string{"zone"}, applyProviderConfigDefaults(arguments, []string{"region", "zone"}))
+	assert.Equal(t, "region", arguments[0].Name)
+	assert.True(t, arguments[0].HasDefault)
+	for _, argument := range arguments[1:] {
+		assert.False(t, argument.HasDefault, argument.Name)
+	}
+}
+
+func TestBuildProviderConfigTemplate(t *testing.T) {
+	template := BuildProviderConfigTemplate(FromTerraformProviderConfigSchema(newTestProviderConfigSchema()))
+	assert.Equal(t, `##  Required. The region of the resources.
+#region: ""
+##  Optional, Sensitive.
+#access_key: ""
+##  Optional.
+#allowed_account_ids: []
+##  Optional.
+#assume_role:
+#  #  Required.
+#  role_arn: ""
+##  Optional.
+#endpoints:
+#  -
+#    #  Optional.
+#    s3: ""
+##  Optional, Deprecated. use retry_mode
+#max_retries: 0
+`, template)
+
+	// Without the first # of every line it is the configuration with the descriptions as comments
+	lines := strings.Split(template, "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimPrefix(line, "#")
+	}
+	config := make(map[string]any)
+	assert.Nil(t, yaml.Unmarshal([]byte(strings.Join(lines, "\n")), &config))
+	assert.Equal(t, map[string]any{"role_arn": ""}, config["assume_role"])
+	assert.Equal(t, []any{map[string]any{"s3": ""}}, config["endpoints"])
+}
@@ -0,0 +1,99 @@
+package generate_selefra_terraform_provider
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTerraformProvider_ResolveProviderConfig(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "secret")
+	assert.Nil(t, os.WriteFile(secretPath, []byte("s3cr3t\n"), 0600))
+	assert.Nil(t, os.Setenv("SELEFRA_TEST_REGION", "us-east-1"))
+	defer func() {
+		_ = os.Unsetenv("SELEFRA_TEST_REGION")
+	}()
+
+	// case 001. a mapping, references are resolved in nested values too
+	terraformProvider := &TerraformProvider{
+		Config: map[string]any{
+			"region":   "${env:SELEFRA_TEST_REGION}",
+			"endpoint": "https://ec2.${env:SELEFRA_TEST_REGION}.amazonaws.com",
+			"assume_role": []any{
+				map[string]any{"secret_key": "${file:" + secretPath + "}"},
+			},
+			"literal":     "$${env:SELEFRA_TEST_REGION}",
+			"max_retries": 3,
+		},
+	}
+	providerConfig, err := terraformProvider.ResolveProviderConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, "us-east-1", providerConfig["region"])
+	assert.Equal(t, "https://ec2.us-east-1.amazonaws.com", providerConfig["endpoint"])
+	assert.Equal(t, "s3cr3t", providerConfig["assume_role"].([]any)[0].(map[string]any)["secret_key"])
+	assert.Equal(t, "${env:SELEFRA_TEST_REGION}", providerConfig["literal"])
+	assert.Equal(t, 3, providerConfig["max_retries"])
+	// the configuration itself still holds the references
+	assert.Equal(t, "${env:SELEFRA_TEST_REGION}", terraformProvider.Config.(map[string]any)["region"])
+
+	// case 002. the legacy JSON string
+	terraformProvider = &TerraformProvider{Config: `{"region": "${env:SELEFRA_TEST_REGION}"}`}
+	providerConfig, err = terraformProvider.ResolveProviderConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, "us-east-1", providerConfig["region"])
+
+	// case 003. a variable that is not set
+	terraformProvider = &TerraformProvider{Config: map[string]any{"region": "${env:SELEFRA_TEST_NOT_SET}"}}
+	_, err = terraformProvider.ResolveProviderConfig()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "region: environment variable SELEFRA_TEST_NOT_SET")
+
+	// case 004. unknown sources are found without resolving anything
+	terraformProvider = &TerraformProvider{Config: map[string]any{"region": "${vault:aws/region}", "profile": "${env:}"}}
+	assert.Equal(t, []string{
+		"profile: reference ${env:} is empty",
+		"region: unknown reference ${vault:aws/region}, it must be ${env:NAME} or ${file:PATH}",
+	}, terraformProvider.checkProviderConfigReferences())
+}
+
+func TestConfig_saveConfigToLocalJson_ProviderConfig(t *testing.T) {
+	workspace := t.TempDir()
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(workspace))
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+	assert.Nil(t, os.Setenv("SELEFRA_TEST_SECRET", "s3cr3t"))
+	defer func() {
+		_ = os.Unsetenv("SELEFRA_TEST_SECRET")
+	}()
+
+	configYaml := `terraform:
+  provider:
+    repo-url: https://example.com/foo/terraform-provider-foo
+    config:
+      access_key: ${env:SELEFRA_TEST_SECRET}
+    execute-files:
+      - download-url: https://example.com/terraform-provider-foo_1.0.0_linux_amd64.zip
+        arch: amd64
+        os: linux
+selefra:
+  module-name: github.com/selefra/selefra-provider-foo
+`
+	configPath := filepath.Join(workspace, "config.yml")
+	assert.Nil(t, os.WriteFile(configPath, []byte(configYaml), 0644))
+	config, err := NewConfigFromOptions(&ConfigOptions{ConfigPath: configPath})
+	assert.Nil(t, err)
+	providerConfig, err := config.Terraform.TerraformProvider.ResolveProviderConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t", providerConfig["access_key"])
+
+	// only the reference is cached
+	cacheBytes, err := os.ReadFile(GetLocalCachePath())
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(cacheBytes), "${env:SELEFRA_TEST_SECRET}"))
+	assert.False(t, strings.Contains(string(cacheBytes), "s3cr3t"))
+}
@@ -0,0 +1,176 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// A sha256 checksum as hex, the way SHA256SUMS and the registry write it
+var sha256SumRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// The files a release publishes the checksums of its archives in, terraform-provider-aws_5.1.0_SHA256SUMS as HashiCorp
+// names it or checksums.txt as goreleaser does by default
+var checksumsFileNameRegex = regexp.MustCompile(`(?i)(sha256sums(\.txt)?|checksums\.txt)$`)
+
+// Whether the file of a listing holds the checksums of the archives rather than being one
+func isChecksumsFileName(name string) bool {
+	return checksumsFileNameRegex.MatchString(name)
+}
+
+// providerChecksumsFile A checksums file found in a listing next to the archives
+type providerChecksumsFile struct {
+	fileName string
+	location string
+}
+
+// The locations of the checksums files, the ones of the version first, they are read until every archive has its sum
+func sortChecksumsFiles(checksumsFiles []*providerChecksumsFile, version string) []string {
+	version = strings.TrimPrefix(version, "v")
+	sorted := make([]*providerChecksumsFile, len(checksumsFiles))
+	copy(sorted, checksumsFiles)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.Contains(sorted[i].fileName, version) && !strings.Contains(sorted[j].fileName, version)
+	})
+	locations := make([]string, 0, len(sorted))
+	for _, checksumsFile := range sorted {
+		locations = append(locations, checksumsFile.location)
+	}
+	return locations
+}
+
+// The sums of a SHA256SUMS file by the file names, a line is the hex sum and the name separated by spaces, a * before
+// the name marks the binary mode of sha256sum
+func parseSha256Sums(content []byte) map[string]string {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		sum := strings.ToLower(fields[0])
+		if !sha256SumRegex.MatchString(sum) {
+			continue
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = sum
+	}
+	return sums
+}
+
+// Read a checksums file, a location without a scheme is a local file
+func loadSha256Sums(ctx context.Context, httpClient *HttpClient, location string) (map[string]string, error) {
+	if !strings.Contains(location, "://") {
+		content, err := os.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: read the checksums file %s error: %s", ErrCheckConfigFailed, location, err.Error())
+		}
+		return parseSha256Sums(content), nil
+	}
+	response, err := httpClient.R(ctx).Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("%w: request %s error: %s", ErrNetwork, location, err.Error())
+	}
+	if !response.IsSuccess() {
+		return nil, fmt.Errorf("%w: request %s failed, status = %s", ErrNetwork, location, response.Status())
+	}
+	return parseSha256Sums(response.Body()), nil
+}
+
+// Give the archives that do not have a sum yet the one of the checksums files, an archive none of them lists is left
+// without, validateExecuteFiles reports it
+func fillArchiveSha256Sums(ctx context.Context, httpClient *HttpClient, archives []*providerArchive, checksumsLocations []string) error {
+	for _, location := range checksumsLocations {
+		missing := false
+		for _, archive := range archives {
+			if archive.sha256Sum == "" {
+				missing = true
+				break
+			}
+		}
+		if !missing {
+			return nil
+		}
+		sums, err := loadSha256Sums(ctx, httpClient, location)
+		if err != nil {
+			return err
+		}
+		for _, archive := range archives {
+			if archive.sha256Sum == "" {
+				archive.sha256Sum = sums[archive.fileName]
+			}
+		}
+	}
+	return nil
+}
+
+// The sha256 of a local file as hex
+func fileSha256Sum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// What is missing or wrong in the files the generated provider downloads the terraform provider from, every field has
+// to be set because the provider is downloaded with nothing else to go on, and one platform has one file only
+func validateExecuteFiles(files []*provider.TerraformProviderFile) []*ConfigProblem {
+	problems := make([]*ConfigProblem, 0)
+	addProblem := func(index int, key, format string, args ...any) {
+		problems = append(problems, &ConfigProblem{
+			Key:     fmt.Sprintf("terraform.provider.execute-files[%d].%s", index, key),
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	platforms := make(map[string]int)
+	for index, file := range files {
+		if file == nil {
+			problems = append(problems, &ConfigProblem{Key: fmt.Sprintf("terraform.provider.execute-files[%d]", index), Message: "is empty"})
+			continue
+		}
+		if file.ProviderName == "" {
+			addProblem(index, "provider-name", "is not set")
+		}
+		if file.ProviderVersion == "" {
+			addProblem(index, "provider-version", "is not set")
+		}
+		if file.DownloadUrl == "" {
+			addProblem(index, "download-url", "is not set")
+		}
+		if file.Sha256Sum == "" {
+			addProblem(index, "sha256-sum", "is not set, the release publishes no checksum of %s", file.DownloadUrl)
+		} else if !sha256SumRegex.MatchString(file.Sha256Sum) {
+			addProblem(index, "sha256-sum", "%s is not a sha256 checksum in hex", file.Sha256Sum)
+		}
+		if !isGoPlatform(file.OS, file.Arch) {
+			addProblem(index, "os", "%s/%s is not a platform go supports", file.OS, file.Arch)
+			continue
+		}
+		platform := file.OS + "/" + file.Arch
+		if first, ok := platforms[platform]; ok {
+			addProblem(index, "arch", "execute-files[%d] is already the file of %s", first, platform)
+		} else {
+			platforms[platform] = index
+		}
+	}
+	return problems
+}
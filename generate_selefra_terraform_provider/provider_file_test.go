@@ -0,0 +1,90 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSha256Sums(t *testing.T) {
+	sums := parseSha256Sums([]byte(strings.Repeat("a", 64) + "  terraform-provider-test_1.0.0_linux_amd64.zip\n" +
+		strings.Repeat("B", 64) + " *terraform-provider-test_1.0.0_darwin_arm64.zip\n" +
+		"abc  terraform-provider-test_1.0.0_windows_amd64.zip\n" +
+		"\n"))
+	assert.Equal(t, map[string]string{
+		"terraform-provider-test_1.0.0_linux_amd64.zip":  strings.Repeat("a", 64),
+		"terraform-provider-test_1.0.0_darwin_arm64.zip": strings.Repeat("b", 64),
+	}, sums)
+
+	assert.True(t, isChecksumsFileName("terraform-provider-test_1.0.0_SHA256SUMS"))
+	assert.True(t, isChecksumsFileName("checksums.txt"))
+	assert.False(t, isChecksumsFileName("terraform-provider-test_1.0.0_SHA256SUMS.sig"))
+}
+
+func TestFillArchiveSha256Sums(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = fmt.Fprintf(w, "%s  terraform-provider-test_1.1.0_linux_amd64.zip\n", strings.Repeat("b", 64))
+	}))
+	defer server.Close()
+	local := filepath.Join(t.TempDir(), "terraform-provider-test_1.0.0_SHA256SUMS")
+	assert.Nil(t, os.WriteFile(local, []byte(strings.Repeat("a", 64)+"  terraform-provider-test_1.0.0_linux_amd64.zip\n"), os.ModePerm))
+
+	// The checksums files of the version are read first, and only until every archive has its sum
+	checksumsFiles := []*providerChecksumsFile{
+		{fileName: "terraform-provider-test_1.0.0_SHA256SUMS", location: local},
+		{fileName: "terraform-provider-test_1.1.0_SHA256SUMS", location: server.URL + "/terraform-provider-test_1.1.0_SHA256SUMS"},
+	}
+	archives := []*providerArchive{{fileName: "terraform-provider-test_1.1.0_linux_amd64.zip"}}
+	assert.Nil(t, fillArchiveSha256Sums(context.Background(), defaultHttpClient, archives, sortChecksumsFiles(checksumsFiles, "v1.1.0")))
+	assert.Equal(t, strings.Repeat("b", 64), archives[0].sha256Sum)
+	assert.Equal(t, 1, requests)
+
+	archives = []*providerArchive{{fileName: "terraform-provider-test_1.0.0_linux_amd64.zip"}, {fileName: "terraform-provider-test_1.0.0_darwin_arm64.zip"}}
+	assert.Nil(t, fillArchiveSha256Sums(context.Background(), defaultHttpClient, archives, sortChecksumsFiles(checksumsFiles, "1.0.0")))
+	assert.Equal(t, strings.Repeat("a", 64), archives[0].sha256Sum)
+	assert.Empty(t, archives[1].sha256Sum)
+	assert.Equal(t, 2, requests)
+
+	err := fillArchiveSha256Sums(context.Background(), defaultHttpClient, archives, []string{filepath.Join(t.TempDir(), "missing")})
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+}
+
+func TestValidateExecuteFiles(t *testing.T) {
+	complete := func(os, arch string) *provider.TerraformProviderFile {
+		return &provider.TerraformProviderFile{
+			ProviderName:    "terraform-provider-test",
+			ProviderVersion: "1.0.0",
+			DownloadUrl:     "https://releases.example.com/terraform-provider-test_1.0.0_" + os + "_" + arch + ".zip",
+			Sha256Sum:       strings.Repeat("a", 64),
+			OS:              os,
+			Arch:            arch,
+		}
+	}
+	assert.Empty(t, validateExecuteFiles([]*provider.TerraformProviderFile{complete("linux", "amd64"), complete("darwin", "arm64")}))
+
+	incomplete := complete("darwin", "arm64")
+	incomplete.ProviderName = ""
+	incomplete.Sha256Sum = "abc"
+	unknown := complete("linux", "x86")
+	problems := validateExecuteFiles([]*provider.TerraformProviderFile{complete("linux", "amd64"), incomplete, complete("linux", "amd64"), unknown, nil})
+	keys := make([]string, 0)
+	for _, problem := range problems {
+		keys = append(keys, problem.Key)
+	}
+	assert.Equal(t, []string{
+		"terraform.provider.execute-files[1].provider-name",
+		"terraform.provider.execute-files[1].sha256-sum",
+		"terraform.provider.execute-files[2].arch",
+		"terraform.provider.execute-files[3].os",
+		"terraform.provider.execute-files[4]",
+	}, keys)
+}
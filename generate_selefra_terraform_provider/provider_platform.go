@@ -0,0 +1,96 @@
+package generate_selefra_terraform_provider
+
+import (
+	"fmt"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"runtime"
+	"strings"
+)
+
+// HostPlatform The platform the scaffold runs on, linux/amd64 for example
+func HostPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// The platforms go can build for, as go tool dist list prints them, and the ones older releases could build for that a
+// provider may still publish files of, windows/arm for example
+var goPlatforms = map[string]struct{}{
+	"aix/ppc64": {}, "android/386": {}, "android/amd64": {}, "android/arm": {}, "android/arm64": {}, "darwin/amd64": {},
+	"darwin/arm64": {}, "dragonfly/amd64": {}, "freebsd/386": {}, "freebsd/amd64": {}, "freebsd/arm": {},
+	"freebsd/arm64": {}, "freebsd/riscv64": {}, "illumos/amd64": {}, "ios/amd64": {}, "ios/arm64": {}, "js/wasm": {},
+	"linux/386": {}, "linux/amd64": {}, "linux/arm": {}, "linux/arm64": {}, "linux/loong64": {}, "linux/mips": {},
+	"linux/mips64": {}, "linux/mips64le": {}, "linux/mipsle": {}, "linux/ppc64": {}, "linux/ppc64le": {},
+	"linux/riscv64": {}, "linux/s390x": {}, "netbsd/386": {}, "netbsd/amd64": {}, "netbsd/arm": {}, "netbsd/arm64": {},
+	"openbsd/386": {}, "openbsd/amd64": {}, "openbsd/arm": {}, "openbsd/arm64": {}, "openbsd/mips64": {},
+	"openbsd/ppc64": {}, "openbsd/riscv64": {}, "plan9/386": {}, "plan9/amd64": {}, "plan9/arm": {},
+	"solaris/amd64": {}, "wasip1/wasm": {}, "windows/386": {}, "windows/amd64": {}, "windows/arm": {},
+	"windows/arm64": {},
+}
+
+// Whether go can build for the operating system and the arch together, so that a word that only looks like one is not
+// taken for it and windows/s390x is not taken for a platform
+func isGoPlatform(os, arch string) bool {
+	_, ok := goPlatforms[os+"/"+arch]
+	return ok
+}
+
+// linux/amd64 -> linux, amd64
+func parsePlatform(platform string) (os, arch string, err error) {
+	split := strings.Split(strings.TrimSpace(platform), "/")
+	if len(split) != 2 || !isGoPlatform(split[0], split[1]) {
+		return "", "", fmt.Errorf("%s is not a platform like linux/amd64", platform)
+	}
+	return split[0], split[1], nil
+}
+
+// Whether one of the files is built for the platform
+func isPlatformCovered(files []*provider.TerraformProviderFile, platform string) bool {
+	for _, file := range files {
+		if file.OS+"/"+file.Arch == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether the platform is one of the list
+func isPlatformListed(platforms []string, platform string) bool {
+	for _, listed := range platforms {
+		if strings.TrimSpace(listed) == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPlatformExecuteFiles The files the generated provider downloads the terraform provider from, the ones of the
+// platforms that are set, or all of them
+func (x *TerraformProvider) GetPlatformExecuteFiles() []*provider.TerraformProviderFile {
+	if len(x.Platforms) == 0 {
+		return x.ExecuteFiles
+	}
+	files := make([]*provider.TerraformProviderFile, 0, len(x.Platforms))
+	for _, file := range x.ExecuteFiles {
+		if isPlatformListed(x.Platforms, file.OS+"/"+file.Arch) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// What is missing for the host and for the platforms that are set, nothing stops the generation but the provider
+// can not be started where there is no file for
+func (x *TerraformProvider) checkPlatforms() []string {
+	warnings := make([]string, 0)
+	if !isPlatformCovered(x.ExecuteFiles, HostPlatform()) {
+		warnings = append(warnings, fmt.Sprintf("no file of the provider is built for %s, its schema can not be read on this host", HostPlatform()))
+	} else if len(x.Platforms) != 0 && !isPlatformListed(x.Platforms, HostPlatform()) {
+		warnings = append(warnings, fmt.Sprintf("%s is not in terraform.provider.platforms, the generated provider will not run on this host", HostPlatform()))
+	}
+	for _, platform := range x.Platforms {
+		if !isPlatformCovered(x.ExecuteFiles, strings.TrimSpace(platform)) {
+			warnings = append(warnings, fmt.Sprintf("no file of the provider is built for %s of terraform.provider.platforms", platform))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,56 @@
+package generate_selefra_terraform_provider
+
+import (
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"testing"
+)
+
+func TestParsePlatform(t *testing.T) {
+	os, arch, err := parsePlatform("linux/amd64")
+	assert.Nil(t, err)
+	assert.Equal(t, "linux", os)
+	assert.Equal(t, "amd64", arch)
+
+	for _, platform := range []string{"linux", "linux/amd64/v2", "linux/x86", "beos/amd64", "windows/s390x", "darwin/386"} {
+		_, _, err = parsePlatform(platform)
+		assert.NotNil(t, err, platform)
+	}
+}
+
+func TestTerraformProvider_GetPlatformExecuteFiles(t *testing.T) {
+	terraformProvider := &TerraformProvider{
+		ExecuteFiles: []*provider.TerraformProviderFile{
+			{OS: "linux", Arch: "amd64"},
+			{OS: "darwin", Arch: "arm64"},
+			{OS: "windows", Arch: "amd64"},
+		},
+	}
+	assert.Len(t, terraformProvider.GetPlatformExecuteFiles(), 3)
+
+	terraformProvider.Platforms = []string{"linux/amd64", " darwin/arm64", "freebsd/amd64"}
+	files := terraformProvider.GetPlatformExecuteFiles()
+	assert.Len(t, files, 2)
+	assert.Equal(t, "linux", files[0].OS)
+	assert.Equal(t, "darwin", files[1].OS)
+}
+
+func TestTerraformProvider_checkPlatforms(t *testing.T) {
+	host := &provider.TerraformProviderFile{OS: runtime.GOOS, Arch: runtime.GOARCH}
+	other := &provider.TerraformProviderFile{OS: "plan9", Arch: "386"}
+
+	terraformProvider := &TerraformProvider{ExecuteFiles: []*provider.TerraformProviderFile{host, other}}
+	assert.Empty(t, terraformProvider.checkPlatforms())
+
+	terraformProvider.Platforms = []string{"plan9/386"}
+	warnings := terraformProvider.checkPlatforms()
+	assert.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "generated provider will not run on this host")
+
+	terraformProvider = &TerraformProvider{ExecuteFiles: []*provider.TerraformProviderFile{other}, Platforms: []string{"plan9/386", "plan9/arm"}}
+	warnings = terraformProvider.checkPlatforms()
+	assert.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "its schema can not be read on this host")
+	assert.Contains(t, warnings[1], "plan9/arm")
+}
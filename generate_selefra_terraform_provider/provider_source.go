@@ -0,0 +1,323 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProviderSourceResolver Finds the executable files of a terraform provider, one resolver knows one kind of place the
+// providers are published to. terraform.provider.version is the version spec to resolve: empty for the latest release,
+// an exact version like 5.1.0, or a constraint like ~> 5.0
+type ProviderSourceResolver interface {
+
+	// Name What terraform.provider.source is set to to use this resolver
+	Name() string
+
+	// Match Whether the provider is published where this resolver looks, only asked when terraform.provider.source is not set
+	Match(terraformProvider *TerraformProvider) bool
+
+	// Resolve The files of the version the spec resolves to, one per platform
+	Resolve(ctx context.Context, terraformProvider *TerraformProvider) ([]*provider.TerraformProviderFile, error)
+}
+
+// ProviderSourceProbe A resolver that asks a remote implements it so that doctor can check the remote is reachable,
+// a resolver without it is not checked
+type ProviderSourceProbe interface {
+
+	// ProbeUrl A cheap url of the remote the provider would be resolved from, answered without a token
+	ProbeUrl(terraformProvider *TerraformProvider) string
+}
+
+const (
+	ProviderSourceLocal             = "local"
+	ProviderSourceHashicorp         = "hashicorp"
+	ProviderSourceGithub            = "github"
+	ProviderSourceGitlab            = "gitlab"
+	ProviderSourceTerraformRegistry = "terraform-registry"
+	ProviderSourceOpenTofu          = "opentofu"
+	ProviderSourceHttpIndex         = "http-index"
+)
+
+// ProviderSourceRegistry The resolvers to choose from, by name or by asking each one in turn whether it matches
+type ProviderSourceRegistry struct {
+	lock      sync.RWMutex
+	resolvers []ProviderSourceResolver
+}
+
+func NewProviderSourceRegistry(resolvers ...ProviderSourceResolver) *ProviderSourceRegistry {
+	return &ProviderSourceRegistry{
+		resolvers: resolvers,
+	}
+}
+
+// NewDefaultProviderSourceRegistry The builtin resolvers, the more specific ones are asked first
+func NewDefaultProviderSourceRegistry() *ProviderSourceRegistry {
+	return NewProviderSourceRegistry(
+		NewLocalProviderResolver(),
+		NewHashicorpReleasesResolver(DefaultHashicorpReleasesUrl),
+		NewGithubReleasesResolver(),
+		NewGitlabReleasesResolver(),
+		NewProviderRegistryResolver(ProviderSourceTerraformRegistry, DefaultTerraformRegistryUrl),
+		NewProviderRegistryResolver(ProviderSourceOpenTofu, DefaultOpenTofuRegistryUrl),
+		NewHttpIndexResolver(),
+	)
+}
+
+// Register Add a resolver, it is asked before the ones already registered and replaces the one with the same name
+func (x *ProviderSourceRegistry) Register(resolver ProviderSourceResolver) {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	resolvers := []ProviderSourceResolver{resolver}
+	for _, registered := range x.resolvers {
+		if registered.Name() != resolver.Name() {
+			resolvers = append(resolvers, registered)
+		}
+	}
+	x.resolvers = resolvers
+}
+
+// Get The resolver of the given name
+func (x *ProviderSourceRegistry) Get(name string) (ProviderSourceResolver, bool) {
+	x.lock.RLock()
+	defer x.lock.RUnlock()
+	for _, resolver := range x.resolvers {
+		if resolver.Name() == name {
+			return resolver, true
+		}
+	}
+	return nil, false
+}
+
+// Names The names of the resolvers in the order they are asked
+func (x *ProviderSourceRegistry) Names() []string {
+	x.lock.RLock()
+	defer x.lock.RUnlock()
+	names := make([]string, 0, len(x.resolvers))
+	for _, resolver := range x.resolvers {
+		names = append(names, resolver.Name())
+	}
+	return names
+}
+
+// Find The resolver named by terraform.provider.source, or else the first one that matches the provider
+func (x *ProviderSourceRegistry) Find(terraformProvider *TerraformProvider) (ProviderSourceResolver, error) {
+	if terraformProvider.Source != "" {
+		resolver, ok := x.Get(terraformProvider.Source)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown terraform.provider.source %s, it must be one of %s", ErrCheckConfigFailed, terraformProvider.Source, strings.Join(x.Names(), ", "))
+		}
+		return resolver, nil
+	}
+	// The resolvers are asked without the lock, Names takes it again and a Register waiting for it would block both
+	x.lock.RLock()
+	resolvers := append([]ProviderSourceResolver(nil), x.resolvers...)
+	x.lock.RUnlock()
+	for _, resolver := range resolvers {
+		if resolver.Match(terraformProvider) {
+			return resolver, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no resolver knows where %s is published, set terraform.provider.source to one of %s, or list the files in terraform.provider.execute-files",
+		ErrCheckConfigFailed, terraformProvider.RepoUrl, strings.Join(x.Names(), ", "))
+}
+
+// Resolve Find the resolver of the provider and let it resolve the files
+func (x *ProviderSourceRegistry) Resolve(ctx context.Context, terraformProvider *TerraformProvider) ([]*provider.TerraformProviderFile, error) {
+	resolver, err := x.Find(terraformProvider)
+	if err != nil {
+		return nil, err
+	}
+	terraformProvider.getLogger().Info("resolve the files of %s with the %s resolver", terraformProvider.RepoUrl, resolver.Name())
+	files, err := resolver.Resolve(ctx, terraformProvider)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: the %s resolver found no file of %s", ErrCheckConfigFailed, resolver.Name(), terraformProvider.RepoUrl)
+	}
+	return files, nil
+}
+
+// The resolvers checkConfig uses
+var defaultProviderSourceRegistry = NewDefaultProviderSourceRegistry()
+
+// RegisterProviderSourceResolver Make a resolver available to every config, for example for an internal artifact store
+func RegisterProviderSourceResolver(resolver ProviderSourceResolver) {
+	defaultProviderSourceRegistry.Register(resolver)
+}
+
+// GetProviderSourceRegistry The resolvers every config uses
+func GetProviderSourceRegistry() *ProviderSourceRegistry {
+	return defaultProviderSourceRegistry
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// SelectProviderVersion The version the spec resolves to among the given ones: the latest release for an empty spec, the
+// same version for an exact one whatever its v prefix, the latest matching one for a constraint like ~> 5.0
+func SelectProviderVersion(versions []string, spec string) (string, error) {
+	type candidate struct {
+		raw     string
+		version *version.Version
+	}
+	candidates := make([]*candidate, 0, len(versions))
+	for _, raw := range versions {
+		if v, err := version.NewVersion(raw); err == nil {
+			candidates = append(candidates, &candidate{raw: raw, version: v})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].version.GreaterThan(candidates[j].version)
+	})
+
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		for _, c := range candidates {
+			if c.version.Prerelease() == "" {
+				return c.raw, nil
+			}
+		}
+		if len(candidates) != 0 {
+			return candidates[0].raw, nil
+		}
+		return "", fmt.Errorf("%w: no version is published", ErrCheckConfigFailed)
+	}
+
+	if exact, err := version.NewVersion(spec); err == nil {
+		for _, c := range candidates {
+			if c.version.Equal(exact) {
+				return c.raw, nil
+			}
+		}
+		return "", fmt.Errorf("%w: version %s is not published", ErrCheckConfigFailed, spec)
+	}
+	constraints, err := version.NewConstraint(spec)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s is neither a version nor a version constraint: %s", ErrCheckConfigFailed, spec, err.Error())
+	}
+	for _, c := range candidates {
+		if constraints.Check(c.version) {
+			return c.raw, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no published version matches %s", ErrCheckConfigFailed, spec)
+}
+
+// Whether the spec names one version rather than a constraint, empty is the latest and not exact either
+func isExactVersionSpec(spec string) bool {
+	if strings.TrimSpace(spec) == "" {
+		return false
+	}
+	_, err := version.NewVersion(strings.TrimSpace(spec))
+	return err == nil
+}
+
+// DefaultProviderAssetPattern terraform-provider-aws_5.1.0_linux_amd64.zip, the way HashiCorp and goreleaser name the
+// archives. A pattern of terraform.provider.asset-pattern must capture os and arch, name and version are optional
+const DefaultProviderAssetPattern = `^(?P<name>terraform-provider-[A-Za-z0-9-]+)_v?(?P<version>[0-9][^_]*)_(?P<os>[a-z0-9]+)_(?P<arch>[a-z0-9]+)\.zip$`
+
+var providerArchiveNameRegex = regexp.MustCompile(DefaultProviderAssetPattern)
+
+// The pattern the assets are matched with, the default one if it is empty
+func compileProviderAssetPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return providerArchiveNameRegex, nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if compiled.SubexpIndex("os") < 0 || compiled.SubexpIndex("arch") < 0 {
+		return nil, fmt.Errorf("%s does not capture (?P<os>...) and (?P<arch>...)", pattern)
+	}
+	return compiled, nil
+}
+
+// providerArchive An archive of a provider found in a listing, see parseProviderArchiveName
+type providerArchive struct {
+	fileName     string
+	providerName string
+	version      string
+	os           string
+	arch         string
+	location     string
+	sha256Sum    string
+}
+
+// A signature, a checksum file or a word that only looks like a platform is not an archive
+func parseProviderArchiveName(pattern *regexp.Regexp, name, location string) (*providerArchive, bool) {
+	match := pattern.FindStringSubmatch(name)
+	if match == nil {
+		return nil, false
+	}
+	group := func(name string) string {
+		if index := pattern.SubexpIndex(name); index >= 0 {
+			return match[index]
+		}
+		return ""
+	}
+	archive := &providerArchive{
+		fileName:     name,
+		providerName: group("name"),
+		version:      strings.TrimPrefix(group("version"), "v"),
+		os:           strings.ToLower(group("os")),
+		arch:         strings.ToLower(group("arch")),
+		location:     location,
+	}
+	if !isGoPlatform(archive.os, archive.arch) {
+		return nil, false
+	}
+	return archive, true
+}
+
+// The archives of the version the spec resolves to among the ones of a listing, the pattern must capture the version
+func selectProviderArchives(archives []*providerArchive, spec string) ([]*providerArchive, string, error) {
+	versionSet := make(map[string]struct{})
+	versions := make([]string, 0)
+	for _, archive := range archives {
+		if _, ok := versionSet[archive.version]; !ok {
+			versionSet[archive.version] = struct{}{}
+			versions = append(versions, archive.version)
+		}
+	}
+	selected, err := SelectProviderVersion(versions, spec)
+	if err != nil {
+		return nil, "", err
+	}
+	selectedArchives := make([]*providerArchive, 0)
+	for _, archive := range archives {
+		if archive.version == selected {
+			selectedArchives = append(selectedArchives, archive)
+		}
+	}
+	return selectedArchives, selected, nil
+}
+
+// The name and the version the archive names do not tell are the ones of the provider and of its release
+func providerArchivesToFiles(archives []*providerArchive, providerName, version string) []*provider.TerraformProviderFile {
+	files := make([]*provider.TerraformProviderFile, 0, len(archives))
+	for _, archive := range archives {
+		file := &provider.TerraformProviderFile{
+			ProviderName:    archive.providerName,
+			ProviderVersion: archive.version,
+			DownloadUrl:     archive.location,
+			Sha256Sum:       archive.sha256Sum,
+			OS:              archive.os,
+			Arch:            archive.arch,
+		}
+		if file.ProviderName == "" {
+			file.ProviderName = providerName
+		}
+		if file.ProviderVersion == "" {
+			file.ProviderVersion = strings.TrimPrefix(version, "v")
+		}
+		files = append(files, file)
+	}
+	return files
+}
@@ -0,0 +1,81 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// GithubReleasesResolver The assets of the GitHub releases of the repository, github.com or the GitHub Enterprise server
+// of terraform.provider.github-api-url
+type GithubReleasesResolver struct {
+}
+
+var _ ProviderSourceResolver = &GithubReleasesResolver{}
+var _ ProviderSourceProbe = &GithubReleasesResolver{}
+
+func NewGithubReleasesResolver() *GithubReleasesResolver {
+	return &GithubReleasesResolver{}
+}
+
+func (x *GithubReleasesResolver) Name() string {
+	return ProviderSourceGithub
+}
+
+func (x *GithubReleasesResolver) Match(terraformProvider *TerraformProvider) bool {
+	isGithubRepo, _ := terraformProvider.IsGithubRepo()
+	return isGithubRepo
+}
+
+// ProbeUrl The rate limit is answered without a token and does not count against it
+func (x *GithubReleasesResolver) ProbeUrl(terraformProvider *TerraformProvider) string {
+	return strings.TrimRight(terraformProvider.GetGithubApiUrl(), "/") + "/rate_limit"
+}
+
+func (x *GithubReleasesResolver) Resolve(ctx context.Context, terraformProvider *TerraformProvider) ([]*provider.TerraformProviderFile, error) {
+	parse, err := url.Parse(terraformProvider.RepoUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCheckConfigFailed, err.Error())
+	}
+	repository := strings.Trim(strings.TrimSuffix(parse.Path, ".git"), "/")
+	client := NewGithubClient(terraformProvider.GetGithubApiUrl(), os.Getenv(EnvGithubToken), terraformProvider.getLogger()).SetHttpClient(terraformProvider.getHttpClient())
+
+	// A constraint needs all the releases, the latest or an exact one is asked for directly
+	if terraformProvider.Version == "" || isExactVersionSpec(terraformProvider.Version) {
+		release, err := client.GetRelease(repository, terraformProvider.Version)
+		if err != nil {
+			return nil, err
+		}
+		return x.parseReleaseFiles(ctx, terraformProvider, release)
+	}
+	releases, err := client.ListReleases(repository)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(releases))
+	for _, release := range releases {
+		tags = append(tags, release.TagName)
+	}
+	selected, err := SelectProviderVersion(tags, terraformProvider.Version)
+	if err != nil {
+		return nil, err
+	}
+	for _, release := range releases {
+		if release.TagName == selected {
+			return x.parseReleaseFiles(ctx, terraformProvider, release)
+		}
+	}
+	return nil, nil
+}
+
+// The files of the release, an asset GitHub has no digest of gets the sum of the checksums file of the release
+func (x *GithubReleasesResolver) parseReleaseFiles(ctx context.Context, terraformProvider *TerraformProvider, release *GithubLatestReleasesResponse) ([]*provider.TerraformProviderFile, error) {
+	archives, checksumsFiles := release.parseProviderArchives(terraformProvider.getAssetPattern())
+	if err := fillArchiveSha256Sums(ctx, terraformProvider.getHttpClient(), archives, sortChecksumsFiles(checksumsFiles, release.TagName)); err != nil {
+		return nil, err
+	}
+	return providerArchivesToFiles(archives, terraformProvider.GetOrParseProviderName(), release.TagName), nil
+}
@@ -0,0 +1,151 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// EnvGitlabToken The token the GitLab API is called with, needed for private projects
+const EnvGitlabToken = "GITLAB_TOKEN"
+
+// GitlabReleasesResolver The asset links of the GitLab releases of the project. Only gitlab.com is matched, a self-hosted
+// GitLab needs terraform.provider.source set to gitlab, its API is found on the host of repo-url
+type GitlabReleasesResolver struct {
+}
+
+var _ ProviderSourceResolver = &GitlabReleasesResolver{}
+var _ ProviderSourceProbe = &GitlabReleasesResolver{}
+
+func NewGitlabReleasesResolver() *GitlabReleasesResolver {
+	return &GitlabReleasesResolver{}
+}
+
+func (x *GitlabReleasesResolver) Name() string {
+	return ProviderSourceGitlab
+}
+
+func (x *GitlabReleasesResolver) Match(terraformProvider *TerraformProvider) bool {
+	parse, err := url.Parse(terraformProvider.RepoUrl)
+	return err == nil && strings.ToLower(parse.Hostname()) == "gitlab.com"
+}
+
+// ProbeUrl The project itself, a private project answers 404 without GITLAB_TOKEN
+func (x *GitlabReleasesResolver) ProbeUrl(terraformProvider *TerraformProvider) string {
+	projectApiUrl, err := gitlabProjectApiUrl(terraformProvider.RepoUrl)
+	if err != nil {
+		return ""
+	}
+	return projectApiUrl
+}
+
+// GitlabRelease A release as the GitLab API returns it
+type GitlabRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  struct {
+		Links []struct {
+			Name           string `json:"name"`
+			URL            string `json:"url"`
+			DirectAssetURL string `json:"direct_asset_url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+func (x *GitlabReleasesResolver) Resolve(ctx context.Context, terraformProvider *TerraformProvider) ([]*provider.TerraformProviderFile, error) {
+	projectApiUrl, err := gitlabProjectApiUrl(terraformProvider.RepoUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCheckConfigFailed, err.Error())
+	}
+
+	var release *GitlabRelease
+	switch {
+	case terraformProvider.Version == "":
+		release = &GitlabRelease{}
+		err = x.getJson(ctx, terraformProvider, projectApiUrl+"/releases/permalink/latest", release)
+	case isExactVersionSpec(terraformProvider.Version):
+		for _, tag := range []string{terraformProvider.Version, "v" + strings.TrimPrefix(terraformProvider.Version, "v")} {
+			release = &GitlabRelease{}
+			if err = x.getJson(ctx, terraformProvider, projectApiUrl+"/releases/"+url.PathEscape(tag), release); err == nil {
+				break
+			}
+		}
+	default:
+		release, err = x.findRelease(ctx, terraformProvider, projectApiUrl, terraformProvider.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	archives := make([]*providerArchive, 0)
+	checksumsFiles := make([]*providerChecksumsFile, 0)
+	for _, link := range release.Assets.Links {
+		downloadUrl := link.DirectAssetURL
+		if downloadUrl == "" {
+			downloadUrl = link.URL
+		}
+		if archive, ok := parseProviderArchiveName(terraformProvider.getAssetPattern(), link.Name, downloadUrl); ok {
+			archives = append(archives, archive)
+		} else if isChecksumsFileName(link.Name) {
+			checksumsFiles = append(checksumsFiles, &providerChecksumsFile{fileName: link.Name, location: downloadUrl})
+		}
+	}
+	if err := fillArchiveSha256Sums(ctx, terraformProvider.getHttpClient(), archives, sortChecksumsFiles(checksumsFiles, release.TagName)); err != nil {
+		return nil, err
+	}
+	return providerArchivesToFiles(archives, terraformProvider.GetOrParseProviderName(), release.TagName), nil
+}
+
+// The latest release whose tag matches the constraint, the releases are listed newest first
+// The API of the project on the host of the repository url
+func gitlabProjectApiUrl(repoUrl string) (string, error) {
+	parse, err := url.Parse(repoUrl)
+	if err != nil {
+		return "", err
+	}
+	// Groups may be nested, the whole path is the id of the project
+	projectPath := strings.Trim(strings.TrimSuffix(parse.Path, ".git"), "/")
+	return fmt.Sprintf("%s://%s/api/v4/projects/%s", parse.Scheme, parse.Host, url.PathEscape(projectPath)), nil
+}
+
+func (x *GitlabReleasesResolver) findRelease(ctx context.Context, terraformProvider *TerraformProvider, projectApiUrl, spec string) (*GitlabRelease, error) {
+	releases := make([]*GitlabRelease, 0)
+	if err := x.getJson(ctx, terraformProvider, projectApiUrl+"/releases?per_page=100", &releases); err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(releases))
+	for _, release := range releases {
+		tags = append(tags, release.TagName)
+	}
+	selected, err := SelectProviderVersion(tags, spec)
+	if err != nil {
+		return nil, err
+	}
+	for _, release := range releases {
+		if release.TagName == selected {
+			return release, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no release matches %s", ErrCheckConfigFailed, spec)
+}
+
+func (x *GitlabReleasesResolver) getJson(ctx context.Context, terraformProvider *TerraformProvider, targetUrl string, v any) error {
+	request := terraformProvider.getHttpClient().R(ctx)
+	if token := os.Getenv(EnvGitlabToken); token != "" {
+		request.SetHeader("PRIVATE-TOKEN", token)
+	}
+	response, err := request.Get(targetUrl)
+	if err != nil {
+		return fmt.Errorf("%w: request %s error: %s", ErrNetwork, targetUrl, err.Error())
+	}
+	if !response.IsSuccess() {
+		return fmt.Errorf("%w: request %s failed, status = %s", ErrNetwork, targetUrl, response.Status())
+	}
+	if err := json.Unmarshal(response.Body(), v); err != nil {
+		return fmt.Errorf("%w: response of %s json unmarshal failed: %s", ErrNetwork, targetUrl, err.Error())
+	}
+	return nil
+}
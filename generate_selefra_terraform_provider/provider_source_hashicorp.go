@@ -0,0 +1,97 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"strings"
+)
+
+// DefaultHashicorpReleasesUrl Where HashiCorp publishes the official providers
+const DefaultHashicorpReleasesUrl = "https://releases.hashicorp.com"
+
+// HashicorpReleasesResolver The official providers, github.com/hashicorp/terraform-provider-*, are published on
+// releases.hashicorp.com together with an index of all their versions and builds
+type HashicorpReleasesResolver struct {
+	baseUrl string
+}
+
+var _ ProviderSourceResolver = &HashicorpReleasesResolver{}
+var _ ProviderSourceProbe = &HashicorpReleasesResolver{}
+
+func NewHashicorpReleasesResolver(baseUrl string) *HashicorpReleasesResolver {
+	return &HashicorpReleasesResolver{
+		baseUrl: strings.TrimRight(baseUrl, "/"),
+	}
+}
+
+func (x *HashicorpReleasesResolver) Name() string {
+	return ProviderSourceHashicorp
+}
+
+func (x *HashicorpReleasesResolver) Match(terraformProvider *TerraformProvider) bool {
+	isOfficialProvider, _ := terraformProvider.IsTerraformOfficialProvider()
+	return isOfficialProvider
+}
+
+func (x *HashicorpReleasesResolver) ProbeUrl(terraformProvider *TerraformProvider) string {
+	return x.baseUrl + "/"
+}
+
+func (x *HashicorpReleasesResolver) Resolve(ctx context.Context, terraformProvider *TerraformProvider) ([]*provider.TerraformProviderFile, error) {
+	providerName := terraformProvider.GetOrParseProviderName()
+	targetUrl := x.baseUrl + "/" + providerName + "/index.json"
+	response, err := terraformProvider.getHttpClient().R(ctx).Get(targetUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: request %s error: %s", ErrNetwork, targetUrl, err.Error())
+	}
+	if !response.IsSuccess() {
+		return nil, fmt.Errorf("%w: request %s failed, status = %s", ErrNetwork, targetUrl, response.Status())
+	}
+
+	index := &struct {
+		Versions map[string]struct {
+			Shasums string `json:"shasums"`
+			Builds  []struct {
+				OS       string `json:"os"`
+				Arch     string `json:"arch"`
+				Filename string `json:"filename"`
+				URL      string `json:"url"`
+			} `json:"builds"`
+		} `json:"versions"`
+	}{}
+	if err := json.Unmarshal(response.Body(), index); err != nil {
+		return nil, fmt.Errorf("%w: the index %s json unmarshal failed: %s", ErrNetwork, targetUrl, err.Error())
+	}
+	versions := make([]string, 0, len(index.Versions))
+	for v := range index.Versions {
+		versions = append(versions, v)
+	}
+	selected, err := SelectProviderVersion(versions, terraformProvider.Version)
+	if err != nil {
+		return nil, err
+	}
+	terraformProvider.getLogger().Info("terraform provider %s, use version %s", providerName, selected)
+
+	release := index.Versions[selected]
+	archives := make([]*providerArchive, 0, len(release.Builds))
+	for _, build := range release.Builds {
+		archives = append(archives, &providerArchive{
+			fileName:     build.Filename,
+			providerName: providerName,
+			version:      selected,
+			os:           build.OS,
+			arch:         build.Arch,
+			location:     build.URL,
+		})
+	}
+	checksumsLocations := make([]string, 0, 1)
+	if release.Shasums != "" {
+		checksumsLocations = append(checksumsLocations, x.baseUrl+"/"+providerName+"/"+selected+"/"+release.Shasums)
+	}
+	if err := fillArchiveSha256Sums(ctx, terraformProvider.getHttpClient(), archives, checksumsLocations); err != nil {
+		return nil, err
+	}
+	return providerArchivesToFiles(archives, providerName, selected), nil
+}
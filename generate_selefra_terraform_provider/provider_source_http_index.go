@@ -0,0 +1,78 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"net/url"
+	"path"
+)
+
+// HttpIndexResolver A web page that links to the release archives of the provider, a directory listing of a web server
+// or an artifact store for example. repo-url is the page, it is never matched and needs terraform.provider.source
+type HttpIndexResolver struct {
+}
+
+var _ ProviderSourceResolver = &HttpIndexResolver{}
+var _ ProviderSourceProbe = &HttpIndexResolver{}
+
+func NewHttpIndexResolver() *HttpIndexResolver {
+	return &HttpIndexResolver{}
+}
+
+func (x *HttpIndexResolver) Name() string {
+	return ProviderSourceHttpIndex
+}
+
+// Match Any url could be an index page, so it has to be asked for
+func (x *HttpIndexResolver) Match(terraformProvider *TerraformProvider) bool {
+	return false
+}
+
+func (x *HttpIndexResolver) ProbeUrl(terraformProvider *TerraformProvider) string {
+	return terraformProvider.RepoUrl
+}
+
+func (x *HttpIndexResolver) Resolve(ctx context.Context, terraformProvider *TerraformProvider) ([]*provider.TerraformProviderFile, error) {
+	indexUrl, err := url.Parse(terraformProvider.RepoUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCheckConfigFailed, err.Error())
+	}
+	response, err := terraformProvider.getHttpClient().R(ctx).Get(terraformProvider.RepoUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: request %s error: %s", ErrNetwork, terraformProvider.RepoUrl, err.Error())
+	}
+	if !response.IsSuccess() {
+		return nil, fmt.Errorf("%w: request %s failed, status = %s", ErrNetwork, terraformProvider.RepoUrl, response.Status())
+	}
+	document, err := goquery.NewDocumentFromReader(bytes.NewReader(response.Body()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: parse the index page %s error: %s", ErrNetwork, terraformProvider.RepoUrl, err.Error())
+	}
+
+	archives := make([]*providerArchive, 0)
+	checksumsFiles := make([]*providerChecksumsFile, 0)
+	document.Find("a[href]").Each(func(i int, selection *goquery.Selection) {
+		href, _ := selection.Attr("href")
+		link, err := indexUrl.Parse(href)
+		if err != nil {
+			return
+		}
+		name := path.Base(link.Path)
+		if archive, ok := parseProviderArchiveName(terraformProvider.getAssetPattern(), name, link.String()); ok {
+			archives = append(archives, archive)
+		} else if isChecksumsFileName(name) {
+			checksumsFiles = append(checksumsFiles, &providerChecksumsFile{fileName: name, location: link.String()})
+		}
+	})
+	selectedArchives, selected, err := selectProviderArchives(archives, terraformProvider.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillArchiveSha256Sums(ctx, terraformProvider.getHttpClient(), selectedArchives, sortChecksumsFiles(checksumsFiles, selected)); err != nil {
+		return nil, err
+	}
+	return providerArchivesToFiles(selectedArchives, terraformProvider.GetOrParseProviderName(), selected), nil
+}
@@ -0,0 +1,64 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalProviderResolver A directory with the release archives of the provider, terraform-provider-aws_5.1.0_linux_amd64.zip
+// and so on, like the packed layout of a terraform filesystem mirror. repo-url is the directory or a file:// url of it
+type LocalProviderResolver struct {
+}
+
+var _ ProviderSourceResolver = &LocalProviderResolver{}
+
+func NewLocalProviderResolver() *LocalProviderResolver {
+	return &LocalProviderResolver{}
+}
+
+func (x *LocalProviderResolver) Name() string {
+	return ProviderSourceLocal
+}
+
+func (x *LocalProviderResolver) Match(terraformProvider *TerraformProvider) bool {
+	if strings.HasPrefix(terraformProvider.RepoUrl, "file://") {
+		return true
+	}
+	if strings.Contains(terraformProvider.RepoUrl, "://") {
+		return false
+	}
+	stat, err := os.Stat(terraformProvider.RepoUrl)
+	return err == nil && stat.IsDir()
+}
+
+func (x *LocalProviderResolver) Resolve(ctx context.Context, terraformProvider *TerraformProvider) ([]*provider.TerraformProviderFile, error) {
+	directory, err := filepath.Abs(strings.TrimPrefix(terraformProvider.RepoUrl, "file://"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCheckConfigFailed, err.Error())
+	}
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read the provider directory %s error: %s", ErrCheckConfigFailed, directory, err.Error())
+	}
+	archives := make([]*providerArchive, 0)
+	for _, entry := range entries {
+		if archive, ok := parseProviderArchiveName(terraformProvider.getAssetPattern(), entry.Name(), filepath.Join(directory, entry.Name())); ok && !entry.IsDir() {
+			archives = append(archives, archive)
+		}
+	}
+	selectedArchives, selected, err := selectProviderArchives(archives, terraformProvider.Version)
+	if err != nil {
+		return nil, err
+	}
+	// The archives are at hand, their sums need no checksums file
+	for _, archive := range selectedArchives {
+		if archive.sha256Sum, err = fileSha256Sum(archive.location); err != nil {
+			return nil, fmt.Errorf("%w: read the provider archive %s error: %s", ErrCheckConfigFailed, archive.location, err.Error())
+		}
+	}
+	return providerArchivesToFiles(selectedArchives, terraformProvider.GetOrParseProviderName(), selected), nil
+}
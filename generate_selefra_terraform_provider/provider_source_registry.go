@@ -0,0 +1,110 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"net/url"
+	"strings"
+)
+
+// ProviderRegistryResolver A registry that speaks the provider registry protocol, the Terraform registry and the OpenTofu
+// registry are the builtin ones. repo-url is the address of the provider on the registry,
+// registry.terraform.io/hashicorp/aws or https://registry.terraform.io/providers/hashicorp/aws
+type ProviderRegistryResolver struct {
+	name        string
+	host        string
+	registryUrl string
+}
+
+var _ ProviderSourceResolver = &ProviderRegistryResolver{}
+var _ ProviderSourceProbe = &ProviderRegistryResolver{}
+
+func NewProviderRegistryResolver(name, registryUrl string) *ProviderRegistryResolver {
+	host := ""
+	if parse, err := url.Parse(registryUrl); err == nil {
+		host = strings.ToLower(parse.Host)
+	}
+	return &ProviderRegistryResolver{
+		name:        name,
+		host:        host,
+		registryUrl: registryUrl,
+	}
+}
+
+func (x *ProviderRegistryResolver) Name() string {
+	return x.name
+}
+
+func (x *ProviderRegistryResolver) Match(terraformProvider *TerraformProvider) bool {
+	host, _, _, ok := parseProviderRegistryAddress(terraformProvider.RepoUrl)
+	return ok && host == x.host
+}
+
+// ProbeUrl The service discovery document, it is the first thing asked for when resolving
+func (x *ProviderRegistryResolver) ProbeUrl(terraformProvider *TerraformProvider) string {
+	return strings.TrimRight(x.registryUrl, "/") + "/.well-known/terraform.json"
+}
+
+func (x *ProviderRegistryResolver) Resolve(ctx context.Context, terraformProvider *TerraformProvider) ([]*provider.TerraformProviderFile, error) {
+	_, namespace, providerType, ok := parseProviderRegistryAddress(terraformProvider.RepoUrl)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not the address of a provider on a registry, <host>/<namespace>/<type>", ErrCheckConfigFailed, terraformProvider.RepoUrl)
+	}
+	registry := NewTerraformRegistry(x.registryUrl).SetHttpClient(terraformProvider.getHttpClient())
+	registryVersions, err := registry.ListVersions(namespace, providerType)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(registryVersions))
+	for _, registryVersion := range registryVersions {
+		versions = append(versions, registryVersion.Version)
+	}
+	selected, err := SelectProviderVersion(versions, terraformProvider.Version)
+	if err != nil {
+		return nil, err
+	}
+	terraformProvider.getLogger().Info("terraform provider %s/%s, use version %s", namespace, providerType, selected)
+
+	files := make([]*provider.TerraformProviderFile, 0)
+	for _, registryVersion := range registryVersions {
+		if registryVersion.Version != selected {
+			continue
+		}
+		for _, platform := range registryVersion.Platforms {
+			download, err := registry.GetDownload(namespace, providerType, selected, platform.OS, platform.Arch)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, &provider.TerraformProviderFile{
+				ProviderName:    "terraform-provider-" + providerType,
+				ProviderVersion: selected,
+				DownloadUrl:     download.DownloadUrl,
+				Sha256Sum:       download.Shasum,
+				OS:              platform.OS,
+				Arch:            platform.Arch,
+			})
+		}
+	}
+	return files, nil
+}
+
+// registry.terraform.io/hashicorp/aws -> registry.terraform.io, hashicorp, aws, the scheme and the providers segment
+// of the web page of the provider are optional
+func parseProviderRegistryAddress(address string) (host, namespace, providerType string, ok bool) {
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+	parse, err := url.Parse(address)
+	if err != nil || parse.Host == "" {
+		return "", "", "", false
+	}
+	segments := strings.Split(strings.Trim(parse.Path, "/"), "/")
+	if len(segments) == 3 && segments[0] == "providers" {
+		segments = segments[1:]
+	}
+	if len(segments) != 2 || segments[0] == "" || segments[1] == "" {
+		return "", "", "", false
+	}
+	return strings.ToLower(parse.Host), segments[0], strings.TrimPrefix(segments[1], "terraform-provider-"), true
+}
@@ -0,0 +1,395 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelectProviderVersion(t *testing.T) {
+	versions := []string{"v1.0.0", "1.1.0", "v2.0.0-beta1", "1.10.2", "1.9.0"}
+
+	selected, err := SelectProviderVersion(versions, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.10.2", selected)
+
+	selected, err = SelectProviderVersion(versions, "1.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1.0.0", selected)
+
+	selected, err = SelectProviderVersion(versions, "~> 1.1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.1.0", selected)
+
+	selected, err = SelectProviderVersion(versions, ">= 1.0, < 1.10")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.9.0", selected)
+
+	_, err = SelectProviderVersion(versions, "3.0.0")
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+
+	_, err = SelectProviderVersion(versions, "not a version")
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+
+	_, err = SelectProviderVersion(nil, "")
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+}
+
+type testProviderSourceResolver struct {
+	name  string
+	files []*provider.TerraformProviderFile
+}
+
+func (x *testProviderSourceResolver) Name() string {
+	return x.name
+}
+
+func (x *testProviderSourceResolver) Match(terraformProvider *TerraformProvider) bool {
+	return terraformProvider.RepoUrl == "https://artifacts.example.com/terraform-provider-test"
+}
+
+func (x *testProviderSourceResolver) Resolve(ctx context.Context, terraformProvider *TerraformProvider) ([]*provider.TerraformProviderFile, error) {
+	return x.files, nil
+}
+
+func TestProviderSourceRegistry(t *testing.T) {
+	registry := NewDefaultProviderSourceRegistry()
+	custom := &testProviderSourceResolver{name: "artifacts", files: []*provider.TerraformProviderFile{{OS: "linux", Arch: "amd64"}}}
+	registry.Register(custom)
+	assert.Equal(t, "artifacts", registry.Names()[0])
+
+	// Found by matching
+	terraformProvider := &TerraformProvider{RepoUrl: "https://artifacts.example.com/terraform-provider-test", logger: NewNopLogger()}
+	files, err := registry.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Equal(t, custom.files, files)
+
+	resolver, err := registry.Find(&TerraformProvider{RepoUrl: "https://github.com/hashicorp/terraform-provider-aws"})
+	assert.Nil(t, err)
+	assert.Equal(t, ProviderSourceHashicorp, resolver.Name())
+
+	resolver, err = registry.Find(&TerraformProvider{RepoUrl: "https://github.com/selefra/terraform-provider-test"})
+	assert.Nil(t, err)
+	assert.Equal(t, ProviderSourceGithub, resolver.Name())
+
+	resolver, err = registry.Find(&TerraformProvider{RepoUrl: "registry.opentofu.org/hashicorp/aws"})
+	assert.Nil(t, err)
+	assert.Equal(t, ProviderSourceOpenTofu, resolver.Name())
+
+	// Found by name
+	resolver, err = registry.Find(&TerraformProvider{RepoUrl: "https://example.com/providers/", Source: ProviderSourceHttpIndex})
+	assert.Nil(t, err)
+	assert.Equal(t, ProviderSourceHttpIndex, resolver.Name())
+
+	_, err = registry.Find(&TerraformProvider{RepoUrl: "https://example.com/providers/"})
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+
+	_, err = registry.Find(&TerraformProvider{RepoUrl: "https://example.com/providers/", Source: "missing"})
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+
+	// Nothing resolved is an error
+	registry.Register(&testProviderSourceResolver{name: "artifacts"})
+	_, err = registry.Resolve(context.Background(), terraformProvider)
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+}
+
+// Registers another resolver while it is asked
+type registeringProviderSourceResolver struct {
+	testProviderSourceResolver
+	registry *ProviderSourceRegistry
+}
+
+func (x *registeringProviderSourceResolver) Match(terraformProvider *TerraformProvider) bool {
+	go x.registry.Register(&testProviderSourceResolver{name: "other"})
+	// Long enough for Register to wait for the lock
+	time.Sleep(50 * time.Millisecond)
+	return false
+}
+
+func TestProviderSourceRegistry_FindWhileRegister(t *testing.T) {
+	registry := NewProviderSourceRegistry()
+	registry.Register(&registeringProviderSourceResolver{testProviderSourceResolver: testProviderSourceResolver{name: "registering"}, registry: registry})
+	errChannel := make(chan error, 1)
+	go func() {
+		_, err := registry.Find(&TerraformProvider{RepoUrl: "https://example.com/providers/"})
+		errChannel <- err
+	}()
+	select {
+	case err := <-errChannel:
+		assert.ErrorIs(t, err, ErrCheckConfigFailed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Find blocks while a resolver is registered")
+	}
+}
+
+func TestLocalProviderResolver(t *testing.T) {
+	directory := t.TempDir()
+	for _, name := range []string{
+		"terraform-provider-test_1.0.0_linux_amd64.zip",
+		"terraform-provider-test_1.1.0_linux_amd64.zip",
+		"terraform-provider-test_1.1.0_darwin_arm64.zip",
+		"terraform-provider-test_1.1.0_SHA256SUMS",
+		"README.md",
+	} {
+		assert.Nil(t, os.WriteFile(filepath.Join(directory, name), []byte{}, os.ModePerm))
+	}
+
+	resolver := NewLocalProviderResolver()
+	terraformProvider := &TerraformProvider{RepoUrl: directory}
+	assert.True(t, resolver.Match(terraformProvider))
+	assert.True(t, resolver.Match(&TerraformProvider{RepoUrl: "file://" + directory}))
+	assert.False(t, resolver.Match(&TerraformProvider{RepoUrl: "https://github.com/selefra/terraform-provider-test"}))
+
+	files, err := resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	for _, file := range files {
+		assert.Equal(t, "terraform-provider-test", file.ProviderName)
+		assert.Equal(t, "1.1.0", file.ProviderVersion)
+		// The sum of the empty archive
+		assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", file.Sha256Sum)
+	}
+
+	terraformProvider.Version = "1.0.0"
+	files, err = resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, filepath.Join(directory, "terraform-provider-test_1.0.0_linux_amd64.zip"), files[0].DownloadUrl)
+}
+
+func TestHttpIndexResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/mirror/terraform-provider-test_1.2.0_SHA256SUMS" {
+			_, _ = fmt.Fprintf(w, "%s  terraform-provider-test_1.2.0_linux_amd64.zip\n%s  terraform-provider-test_1.2.0_windows_amd64.zip\n", strings.Repeat("a", 64), strings.Repeat("b", 64))
+			return
+		}
+		_, _ = fmt.Fprint(w, `<html><body>
+<a href="../">../</a>
+<a href="/mirror/terraform-provider-test_1.2.0_SHA256SUMS">SHA256SUMS</a>
+<a href="terraform-provider-test_1.0.0_linux_amd64.zip">1.0.0</a>
+<a href="/mirror/terraform-provider-test_1.2.0_linux_amd64.zip">1.2.0</a>
+<a href="https://cdn.example.com/terraform-provider-test_1.2.0_windows_amd64.zip">1.2.0</a>
+</body></html>`)
+	}))
+	defer server.Close()
+
+	resolver := NewHttpIndexResolver()
+	terraformProvider := &TerraformProvider{RepoUrl: server.URL + "/providers/test/", Source: ProviderSourceHttpIndex}
+	assert.False(t, resolver.Match(terraformProvider))
+
+	files, err := resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, server.URL+"/mirror/terraform-provider-test_1.2.0_linux_amd64.zip", files[0].DownloadUrl)
+	assert.Equal(t, "https://cdn.example.com/terraform-provider-test_1.2.0_windows_amd64.zip", files[1].DownloadUrl)
+	assert.Equal(t, strings.Repeat("a", 64), files[0].Sha256Sum)
+	assert.Equal(t, strings.Repeat("b", 64), files[1].Sha256Sum)
+
+	terraformProvider.Version = "< 1.2"
+	files, err = resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, server.URL+"/providers/test/terraform-provider-test_1.0.0_linux_amd64.zip", files[0].DownloadUrl)
+	assert.Empty(t, files[0].Sha256Sum)
+}
+
+func TestHashicorpReleasesResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/terraform-provider-test/1.1.0/terraform-provider-test_1.1.0_SHA256SUMS" {
+			_, _ = fmt.Fprintf(w, "%s  terraform-provider-test_1.1.0_linux_amd64.zip\n%s  terraform-provider-test_1.1.0_darwin_arm64.zip\n", strings.Repeat("a", 64), strings.Repeat("b", 64))
+			return
+		}
+		if r.URL.Path != "/terraform-provider-test/index.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"name": "terraform-provider-test", "versions": {
+"1.0.0": {"builds": [{"os": "linux", "arch": "amd64", "url": "https://releases.example.com/1.0.0_linux_amd64.zip"}]},
+"1.1.0": {"shasums": "terraform-provider-test_1.1.0_SHA256SUMS", "builds": [
+	{"os": "linux", "arch": "amd64", "filename": "terraform-provider-test_1.1.0_linux_amd64.zip", "url": "https://releases.example.com/1.1.0_linux_amd64.zip"},
+	{"os": "darwin", "arch": "arm64", "filename": "terraform-provider-test_1.1.0_darwin_arm64.zip", "url": "https://releases.example.com/1.1.0_darwin_arm64.zip"}
+]},
+"1.2.0-alpha1": {"builds": [{"os": "linux", "arch": "amd64", "url": "https://releases.example.com/1.2.0-alpha1_linux_amd64.zip"}]}
+}}`)
+	}))
+	defer server.Close()
+
+	resolver := NewHashicorpReleasesResolver(server.URL)
+	terraformProvider := &TerraformProvider{RepoUrl: "https://github.com/hashicorp/terraform-provider-test", logger: NewNopLogger()}
+	assert.True(t, resolver.Match(terraformProvider))
+
+	files, err := resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, "1.1.0", files[0].ProviderVersion)
+	assert.Equal(t, "terraform-provider-test", files[0].ProviderName)
+	assert.Equal(t, strings.Repeat("a", 64), files[0].Sha256Sum)
+	assert.Equal(t, strings.Repeat("b", 64), files[1].Sha256Sum)
+
+	terraformProvider.Version = "~> 1.0.0"
+	files, err = resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "https://releases.example.com/1.0.0_linux_amd64.zip", files[0].DownloadUrl)
+}
+
+func TestGithubReleasesResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/selefra/terraform-provider-test/releases/latest":
+			_, _ = fmt.Fprintf(w, `{"tag_name": "v1.1.0", "name": "v1.1.0", "assets": [{"name": "terraform-provider-test_1.1.0_linux_amd64.zip", "browser_download_url": "https://github.example.com/1.1.0_linux_amd64.zip", "digest": "sha256:%s"}]}`, strings.Repeat("a", 64))
+		case "/api/v3/repos/selefra/terraform-provider-test/releases":
+			_, _ = fmt.Fprint(w, `[
+{"tag_name": "v1.1.0", "name": "v1.1.0", "assets": [{"name": "terraform-provider-test_1.1.0_linux_amd64.zip", "browser_download_url": "https://github.example.com/1.1.0_linux_amd64.zip"}]},
+{"tag_name": "v1.0.1", "name": "v1.0.1", "assets": [{"name": "terraform-provider-test_1.0.1_linux_amd64.zip", "browser_download_url": "https://github.example.com/1.0.1_linux_amd64.zip"}, {"name": "checksums.txt", "browser_download_url": "http://`+r.Host+`/download/v1.0.1/checksums.txt"}]}
+]`)
+		case "/download/v1.0.1/checksums.txt":
+			_, _ = fmt.Fprintf(w, "%s *terraform-provider-test_1.0.1_linux_amd64.zip\n", strings.Repeat("B", 64))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewGithubReleasesResolver()
+	terraformProvider := &TerraformProvider{RepoUrl: server.URL + "/selefra/terraform-provider-test", GithubApiUrl: server.URL + "/api/v3", logger: NewNopLogger()}
+	assert.True(t, resolver.Match(terraformProvider))
+
+	files, err := resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "https://github.example.com/1.1.0_linux_amd64.zip", files[0].DownloadUrl)
+	assert.Equal(t, strings.Repeat("a", 64), files[0].Sha256Sum)
+
+	terraformProvider.Version = "~> 1.0.0"
+	files, err = resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "https://github.example.com/1.0.1_linux_amd64.zip", files[0].DownloadUrl)
+	assert.Equal(t, strings.Repeat("b", 64), files[0].Sha256Sum)
+}
+
+func TestGitlabReleasesResolver(t *testing.T) {
+	t.Setenv(EnvGitlabToken, "test-token")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-token", r.Header.Get("PRIVATE-TOKEN"))
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/infra%2Fproviders%2Fterraform-provider-test/releases/permalink/latest":
+			_, _ = fmt.Fprint(w, `{"tag_name": "v1.1.0", "assets": {"links": [{"name": "terraform-provider-test_1.1.0_linux_amd64.zip", "url": "https://gitlab.example.com/1.1.0_linux_amd64.zip"}]}}`)
+		case "/api/v4/projects/infra%2Fproviders%2Fterraform-provider-test/releases/v1.0.0":
+			_, _ = fmt.Fprint(w, `{"tag_name": "v1.0.0", "assets": {"links": [{"name": "terraform-provider-test_1.0.0_linux_amd64.zip", "url": "https://gitlab.example.com/1.0.0_linux_amd64.zip", "direct_asset_url": "https://gitlab.example.com/direct/1.0.0_linux_amd64.zip"}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewGitlabReleasesResolver()
+	assert.True(t, resolver.Match(&TerraformProvider{RepoUrl: "https://gitlab.com/infra/terraform-provider-test"}))
+	terraformProvider := &TerraformProvider{RepoUrl: server.URL + "/infra/providers/terraform-provider-test.git", Source: ProviderSourceGitlab}
+	assert.False(t, resolver.Match(terraformProvider))
+
+	files, err := resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "1.1.0", files[0].ProviderVersion)
+	assert.Equal(t, "https://gitlab.example.com/1.1.0_linux_amd64.zip", files[0].DownloadUrl)
+
+	terraformProvider.Version = "1.0.0"
+	files, err = resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "https://gitlab.example.com/direct/1.0.0_linux_amd64.zip", files[0].DownloadUrl)
+}
+
+func TestProviderRegistryResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/terraform.json":
+			_, _ = fmt.Fprint(w, `{"providers.v1": "/v1/providers/"}`)
+		case "/v1/providers/selefra/test/versions":
+			_, _ = fmt.Fprint(w, `{"versions": [
+{"version": "1.0.0", "platforms": [{"os": "linux", "arch": "amd64"}]},
+{"version": "1.1.0", "platforms": [{"os": "linux", "arch": "amd64"}, {"os": "darwin", "arch": "arm64"}]}
+]}`)
+		case "/v1/providers/selefra/test/1.1.0/download/linux/amd64", "/v1/providers/selefra/test/1.1.0/download/darwin/arm64",
+			"/v1/providers/selefra/test/1.0.0/download/linux/amd64":
+			_, _ = fmt.Fprintf(w, `{"download_url": "https://registry.example.com%s.zip", "shasum": "abc"}`, r.URL.Path)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewProviderRegistryResolver("test-registry", server.URL)
+	host := server.Listener.Addr().String()
+	terraformProvider := &TerraformProvider{RepoUrl: host + "/selefra/test", logger: NewNopLogger()}
+	assert.True(t, resolver.Match(terraformProvider))
+	assert.True(t, resolver.Match(&TerraformProvider{RepoUrl: "https://" + host + "/providers/selefra/terraform-provider-test"}))
+	assert.False(t, resolver.Match(&TerraformProvider{RepoUrl: "registry.terraform.io/selefra/test"}))
+
+	files, err := resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, "1.1.0", files[0].ProviderVersion)
+	assert.Equal(t, "abc", files[0].Sha256Sum)
+	assert.Equal(t, "https://registry.example.com/v1/providers/selefra/test/1.1.0/download/linux/amd64.zip", files[0].DownloadUrl)
+
+	terraformProvider.Version = "< 1.1"
+	files, err = resolver.Resolve(context.Background(), terraformProvider)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "1.0.0", files[0].ProviderVersion)
+}
+
+func TestGithubLatestReleasesResponse_ParseProviderFiles(t *testing.T) {
+	assets := make([]map[string]string, 0)
+	for _, name := range []string{
+		"terraform-provider-test_1.2.0_linux_amd64.zip",
+		"terraform-provider-test_1.2.0_linux_amd64.zip.sig",
+		"terraform-provider-test_1.2.0_darwin_arm64.zip",
+		"terraform-provider-test_1.2.0_SHA256SUMS",
+		"terraform-provider-test_1.2.0_SHA256SUMS.linux_amd64",
+		"terraform-provider-test_1.2.0_linux_x86.zip",
+		"my-provider-windows-amd64.zip",
+	} {
+		assets = append(assets, map[string]string{"name": name, "browser_download_url": "https://github.example.com/" + name})
+	}
+	body, err := json.Marshal(map[string]any{"tag_name": "v1.2.0", "name": "Release 1.2.0", "assets": assets})
+	assert.Nil(t, err)
+	release := &GithubLatestReleasesResponse{}
+	assert.Nil(t, json.Unmarshal(body, release))
+
+	files := release.ParseProviderFileSlice()
+	assert.Len(t, files, 2)
+	for _, file := range files {
+		assert.Equal(t, "terraform-provider-test", file.ProviderName)
+		assert.Equal(t, "1.2.0", file.ProviderVersion)
+	}
+	assert.Equal(t, "https://github.example.com/terraform-provider-test_1.2.0_linux_amd64.zip", files[0].DownloadUrl)
+
+	// The name and the version come from the provider and the tag
+	pattern, err := compileProviderAssetPattern(`^my-provider-(?P<os>[a-z0-9]+)-(?P<arch>[a-z0-9]+)\.zip$`)
+	assert.Nil(t, err)
+	files = release.ParseProviderFiles(pattern, "terraform-provider-my")
+	assert.Len(t, files, 1)
+	assert.Equal(t, &provider.TerraformProviderFile{
+		ProviderName:    "terraform-provider-my",
+		ProviderVersion: "1.2.0",
+		DownloadUrl:     "https://github.example.com/my-provider-windows-amd64.zip",
+		OS:              "windows",
+		Arch:            "amd64",
+	}, files[0])
+
+	_, err = compileProviderAssetPattern(`^my-provider-(?P<os>[a-z0-9]+)\.zip$`)
+	assert.NotNil(t, err)
+}
@@ -13,6 +13,45 @@ type SelefraProviderRenderParams struct {
 	// The current table generator depends on which packages need to be imported
 	ImportSet  map[string]struct{}
 	ModuleName string
+
+	// Whether the provider starts one client for every entry of clients in its configuration, see Output.ClientMode
+	MultiClient bool
+}
+
+// The columns every table gets when the provider has several clients, the value is the field of the client that
+// pulled the row
+var clientColumns = []*SelefraColumnSchemaRenderParams{
+	{
+		ColumnName:                "account_id",
+		Description:               "The account of the client the row was pulled by, account_id of its entry of clients",
+		ColumnTypeCodeString:      "schema.ColumnTypeString",
+		ExtractorInlineCodeString: "clientColumnValueExtractor(func(client *Client) string { return client.AccountID })",
+	},
+	{
+		ColumnName:                "region",
+		Description:               "The region of the client the row was pulled by, region of its entry of clients",
+		ColumnTypeCodeString:      "schema.ColumnTypeString",
+		ExtractorInlineCodeString: "clientColumnValueExtractor(func(client *Client) string { return client.Region })",
+	},
+}
+
+// AddClientColumns Give every table the account_id and region columns of the client that pulled the row, a table whose
+// resource has an attribute of that name already keeps the attribute
+func (x *SelefraProviderRenderParams) AddClientColumns() {
+	x.MultiClient = true
+	for _, table := range x.TableSlice {
+		columnSet := make(map[string]struct{}, len(table.ColumnSchemaSlice))
+		for _, column := range table.ColumnSchemaSlice {
+			columnSet[column.ColumnName] = struct{}{}
+		}
+		for _, clientColumn := range clientColumns {
+			if _, exists := columnSet[clientColumn.ColumnName]; exists {
+				continue
+			}
+			column := *clientColumn
+			table.ColumnSchemaSlice = append(table.ColumnSchemaSlice, &column)
+		}
+	}
 }
 
 func (x *SelefraProviderRenderParams) MergeDependencyImports(tableSchemaRenderParams *SelefraTableSchemaRenderParams) {
@@ -38,7 +77,7 @@ type SelefraTableSchemaRenderParams struct {
 	// Resource name of terraform
 	ResourceName string
 
-	// Description of the table
+	// Description of the table, as it is, templates quote it with backtick or quote
 	Description string
 
 	// All the columns in the table
@@ -80,7 +119,7 @@ type SelefraColumnSchemaRenderParams struct {
 	// Name of column
 	ColumnName string
 
-	// Description of the column
+	// Description of the column, as it is, templates quote it with backtick or quote
 	Description string
 
 	// The code snippet corresponding to the column type
@@ -0,0 +1,41 @@
+package generate_selefra_terraform_provider
+
+import (
+	"github.com/selefra/selefra-provider-sdk/provider/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSelefraProviderRenderParams_AddClientColumns(t *testing.T) {
+	ir := &TerraformProviderSchemaIR{
+		ProviderName: "terraform-provider-test",
+		Resources: []*TerraformResourceSchemaIR{
+			{
+				ResourceName: "test_instance",
+				Columns:      []*TerraformColumnSchemaIR{{ColumnName: "id", ColumnType: schema.ColumnTypeString}},
+			},
+			{
+				ResourceName: "test_bucket",
+				Columns: []*TerraformColumnSchemaIR{
+					{ColumnName: "id", ColumnType: schema.ColumnTypeString},
+					{ColumnName: "region", ColumnType: schema.ColumnTypeString},
+				},
+			},
+		},
+	}
+	renderParams := ir.ToSelefraProviderRenderParams("github.com/selefra/selefra-provider-test")
+	renderParams.AddClientColumns()
+	assert.True(t, renderParams.MultiClient)
+
+	columnNames := func(table *SelefraTableSchemaRenderParams) []string {
+		names := make([]string, 0)
+		for _, column := range table.ColumnSchemaSlice {
+			names = append(names, column.ColumnName)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"id", "selefra_terraform_original_result", "account_id", "region"}, columnNames(renderParams.TableSlice[0]))
+	// The region of the resource is kept
+	assert.Equal(t, []string{"id", "region", "selefra_terraform_original_result", "account_id"}, columnNames(renderParams.TableSlice[1]))
+	assert.Equal(t, "clientColumnValueExtractor(func(client *Client) string { return client.AccountID })", renderParams.TableSlice[0].ColumnSchemaSlice[2].ExtractorInlineCodeString)
+}
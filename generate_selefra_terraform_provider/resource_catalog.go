@@ -0,0 +1,225 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	ResourceKindResource   = "resource"
+	ResourceKindDataSource = "data_source"
+)
+
+// TerraformResourceSummaryIR What a resource or data source of the provider looks like, enough to decide whether to
+// generate a table for it without starting the provider again
+type TerraformResourceSummaryIR struct {
+	ResourceName string `json:"resource_name"`
+
+	// The description of the resource, empty when the provider does not describe its resources
+	Description string `json:"description,omitempty"`
+
+	// ResourceKindResource or ResourceKindDataSource
+	Kind string `json:"kind"`
+
+	// The top level attributes and blocks
+	Columns int `json:"columns"`
+
+	// Only the resources with an id column can become a table
+	HasId bool `json:"has_id"`
+
+	// The top level attributes that are blocks with attributes of their own
+	NestedBlocks int `json:"nested_blocks"`
+
+	// The deprecation message, empty if it is not deprecated
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+// The shim has no description for a resource, a provider whose resources have one can implement this
+type describedResource interface {
+	Description() string
+}
+
+func FromTerraformResourceSummary(terraformResourceName, kind string, terraformResourceSchema shim.Resource) *TerraformResourceSummaryIR {
+	summary := &TerraformResourceSummaryIR{
+		ResourceName: terraformResourceName,
+		Kind:         kind,
+		Deprecated:   terraformResourceSchema.DeprecationMessage(),
+	}
+	if described, ok := terraformResourceSchema.(describedResource); ok {
+		summary.Description = strings.TrimSpace(described.Description())
+	}
+	terraformResourceSchema.Schema().Range(func(terraformColumnName string, terraformColumnSchema shim.Schema) bool {
+		summary.Columns++
+		if FromTerraformColumnSchema(terraformColumnName, terraformColumnSchema).IsID() {
+			summary.HasId = true
+		}
+		if _, ok := terraformColumnSchema.Elem().(shim.Resource); ok {
+			summary.NestedBlocks++
+		}
+		return true
+	})
+	return summary
+}
+
+// Every resource and data source of the provider, the resources come first and each kind is sorted by name
+func fromTerraformProviderCatalog(provider shim.Provider) []*TerraformResourceSummaryIR {
+	catalog := make([]*TerraformResourceSummaryIR, 0)
+	provider.ResourcesMap().Range(func(terraformResourceName string, terraformResourceSchema shim.Resource) bool {
+		catalog = append(catalog, FromTerraformResourceSummary(terraformResourceName, ResourceKindResource, terraformResourceSchema))
+		return true
+	})
+	provider.DataSourcesMap().Range(func(terraformResourceName string, terraformResourceSchema shim.Resource) bool {
+		catalog = append(catalog, FromTerraformResourceSummary(terraformResourceName, ResourceKindDataSource, terraformResourceSchema))
+		return true
+	})
+	sort.SliceStable(catalog, func(i, j int) bool {
+		if catalog[i].Kind != catalog[j].Kind {
+			return catalog[i].Kind == ResourceKindResource
+		}
+		return catalog[i].ResourceName < catalog[j].ResourceName
+	})
+	return catalog
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// ResourceListOptions Which entries of the catalog to list
+type ResourceListOptions struct {
+
+	// Glob patterns like aws_s3_*, an entry is listed when any of them matches its name, all entries if empty
+	Patterns []string
+
+	// ResourceKindResource or ResourceKindDataSource, both if empty
+	Kind string
+
+	// Text an entry is listed for when its name, description or deprecation message contains it, case-insensitively,
+	// all entries if empty
+	Search string
+}
+
+// ResourceListEntry A resource or data source of the provider and whether generate would render a table for it
+type ResourceListEntry struct {
+	*TerraformResourceSummaryIR
+
+	// Only resources are generated, and only when they have an id and are selected by terraform.provider.resources
+	Generated bool `json:"generated"`
+}
+
+// ListResources The resources and data sources of the provider, read from schema.json, the provider is only started when
+// there is no schema.json or it was written before the catalog was part of it
+func (x *SchemaIRManager) ListResources(ctx context.Context, options *ResourceListOptions) ([]*ResourceListEntry, error) {
+	if options.Kind != "" && options.Kind != ResourceKindResource && options.Kind != ResourceKindDataSource {
+		return nil, fmt.Errorf("%w: unknown kind %s, it must be %s or %s", ErrCheckConfigFailed, options.Kind, ResourceKindResource, ResourceKindDataSource)
+	}
+	for _, pattern := range options.Patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%w: bad pattern %s: %s", ErrCheckConfigFailed, pattern, err.Error())
+		}
+	}
+
+	ir, err := x.ReadOrGenerateSchemaIR(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ir.Catalog == nil {
+		x.config.GetLogger().Info("schema.json has no catalog of the resources, so generate it again...")
+		if err := x.GenerateIRAndSave(ctx); err != nil {
+			return nil, err
+		}
+		if ir, err = x.readTerraformSchemaIR(); err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make([]*ResourceListEntry, 0)
+	for _, summary := range ir.Catalog {
+		if options.Kind != "" && summary.Kind != options.Kind {
+			continue
+		}
+		if !matchAnyPattern(options.Patterns, summary.ResourceName) {
+			continue
+		}
+		if !matchSearch(options.Search, summary) {
+			continue
+		}
+		entries = append(entries, &ResourceListEntry{
+			TerraformResourceSummaryIR: summary,
+			Generated:                  summary.Kind == ResourceKindResource && summary.HasId && x.config.IsResourceNeedGenerate(summary.ResourceName),
+		})
+	}
+	return entries, nil
+}
+
+func matchAnyPattern(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSearch(search string, summary *TerraformResourceSummaryIR) bool {
+	search = strings.ToLower(strings.TrimSpace(search))
+	if search == "" {
+		return true
+	}
+	for _, text := range []string{summary.ResourceName, summary.Description, summary.Deprecated} {
+		if strings.Contains(strings.ToLower(text), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteResourceListTable One aligned line per entry
+func WriteResourceListTable(writer io.Writer, entries []*ResourceListEntry) error {
+	tableWriter := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tableWriter, "NAME\tKIND\tCOLUMNS\tID\tNESTED BLOCKS\tGENERATED\tDEPRECATED")
+	for _, entry := range entries {
+		deprecated := "-"
+		if entry.Deprecated != "" {
+			deprecated = entry.Deprecated
+		}
+		_, _ = fmt.Fprintf(tableWriter, "%s\t%s\t%d\t%s\t%d\t%s\t%s\n", entry.ResourceName, entry.Kind, entry.Columns,
+			formatYesNo(entry.HasId), entry.NestedBlocks, formatYesNo(entry.Generated), deprecated)
+	}
+	return tableWriter.Flush()
+}
+
+// WriteResourceListCsv The entries as CSV with a header row, the deprecated column holds the deprecation message
+func WriteResourceListCsv(writer io.Writer, entries []*ResourceListEntry) error {
+	csvWriter := csv.NewWriter(writer)
+	_ = csvWriter.Write([]string{"name", "kind", "columns", "has_id", "nested_blocks", "generated", "deprecated"})
+	for _, entry := range entries {
+		_ = csvWriter.Write([]string{
+			entry.ResourceName,
+			entry.Kind,
+			strconv.Itoa(entry.Columns),
+			strconv.FormatBool(entry.HasId),
+			strconv.Itoa(entry.NestedBlocks),
+			strconv.FormatBool(entry.Generated),
+			entry.Deprecated,
+		})
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func formatYesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,123 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"context"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type testDescribedResource struct {
+	shim.Resource
+	description string
+}
+
+func (x testDescribedResource) Description() string {
+	return x.description
+}
+
+func newTestShimProvider() shim.Provider {
+	stringColumn := (&shimschema.Schema{Type: shim.TypeString}).Shim()
+	block := (&shimschema.Schema{
+		Type: shim.TypeList,
+		Elem: (&shimschema.Resource{Schema: shimschema.SchemaMap{"enabled": stringColumn}}).Shim(),
+	}).Shim()
+	return (&shimschema.Provider{
+		ResourcesMap: shimschema.ResourceMap{
+			"test_bucket": testDescribedResource{
+				Resource: (&shimschema.Resource{Schema: shimschema.SchemaMap{
+					"id":         stringColumn,
+					"name":       stringColumn,
+					"versioning": block,
+				}}).Shim(),
+				description: "A storage Bucket. ",
+			},
+			"test_attachment": (&shimschema.Resource{
+				Schema:             shimschema.SchemaMap{"bucket": stringColumn},
+				DeprecationMessage: "use test_bucket instead",
+			}).Shim(),
+		},
+		DataSourcesMap: shimschema.ResourceMap{
+			"test_bucket": (&shimschema.Resource{Schema: shimschema.SchemaMap{"id": stringColumn}}).Shim(),
+		},
+	}).Shim()
+}
+
+func TestFromTerraformProviderCatalog(t *testing.T) {
+	catalog := fromTerraformProviderCatalog(newTestShimProvider())
+	assert.Equal(t, []*TerraformResourceSummaryIR{
+		{ResourceName: "test_attachment", Kind: ResourceKindResource, Columns: 1, Deprecated: "use test_bucket instead"},
+		{ResourceName: "test_bucket", Description: "A storage Bucket.", Kind: ResourceKindResource, Columns: 3, HasId: true, NestedBlocks: 1},
+		{ResourceName: "test_bucket", Kind: ResourceKindDataSource, Columns: 1, HasId: true},
+	}, catalog)
+}
+
+func TestSchemaIRManager_ListResources(t *testing.T) {
+	config := &Config{}
+	config.SetLogger(NewNopLogger())
+	config.SetFileSystem(NewMemoryFileSystem(NewOsFileSystem()))
+	config.Output.Directory = t.TempDir()
+	config.Terraform.TerraformProvider.Resources = []string{"test_bucket"}
+
+	// The catalog lists everything even though only test_bucket is selected
+	schemaIRManager := NewSchemaIRManager(config)
+	assert.Nil(t, schemaIRManager.saveTerraformSchemaIR(FromTerraformProviderSchema("test", newTestShimProvider(), config)))
+
+	entries, err := schemaIRManager.ListResources(context.Background(), &ResourceListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 3)
+	assert.False(t, entries[0].Generated)
+	assert.True(t, entries[1].Generated)
+	assert.False(t, entries[2].Generated)
+
+	entries, err = schemaIRManager.ListResources(context.Background(), &ResourceListOptions{Patterns: []string{"*_bucket"}, Kind: ResourceKindDataSource})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, ResourceKindDataSource, entries[0].Kind)
+
+	// The search matches the description of test_bucket and the deprecation message of test_attachment
+	entries, err = schemaIRManager.ListResources(context.Background(), &ResourceListOptions{Search: "STORAGE bucket"})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "test_bucket", entries[0].ResourceName)
+	entries, err = schemaIRManager.ListResources(context.Background(), &ResourceListOptions{Search: "Instead", Kind: ResourceKindResource})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "test_attachment", entries[0].ResourceName)
+	entries, err = schemaIRManager.ListResources(context.Background(), &ResourceListOptions{Search: "_BUCKET", Kind: ResourceKindDataSource})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	entries, err = schemaIRManager.ListResources(context.Background(), &ResourceListOptions{Search: "queue"})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 0)
+
+	_, err = schemaIRManager.ListResources(context.Background(), &ResourceListOptions{Kind: "module"})
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+	_, err = schemaIRManager.ListResources(context.Background(), &ResourceListOptions{Patterns: []string{"["}})
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+}
+
+func TestWriteResourceList(t *testing.T) {
+	entries := []*ResourceListEntry{
+		{TerraformResourceSummaryIR: &TerraformResourceSummaryIR{ResourceName: "test_bucket", Description: "A storage Bucket.", Kind: ResourceKindResource, Columns: 3, HasId: true, NestedBlocks: 1}, Generated: true},
+		{TerraformResourceSummaryIR: &TerraformResourceSummaryIR{ResourceName: "test_attachment", Kind: ResourceKindResource, Columns: 1, Deprecated: "use test_bucket, instead"}},
+	}
+
+	buffer := bytes.Buffer{}
+	assert.Nil(t, WriteResourceListCsv(&buffer, entries))
+	assert.Equal(t, `name,kind,columns,has_id,nested_blocks,generated,deprecated
+test_bucket,resource,3,true,1,true,
+test_attachment,resource,1,false,0,false,"use test_bucket, instead"
+`, buffer.String())
+
+	buffer.Reset()
+	assert.Nil(t, WriteResourceListTable(&buffer, entries))
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "NAME"))
+	assert.Contains(t, lines[1], "yes")
+	assert.Contains(t, lines[2], "use test_bucket, instead")
+}
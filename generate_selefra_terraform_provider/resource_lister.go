@@ -0,0 +1,216 @@
+package generate_selefra_terraform_provider
+
+import (
+	"fmt"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"sort"
+	"strings"
+)
+
+// TerraformListDataSourceIR A data source of the provider that lists the ids of some resource and can be read without
+// arguments, such as aws_vpcs
+type TerraformListDataSourceIR struct {
+	DataSourceName string `json:"data_source_name"`
+
+	// The computed attribute the ids are in, a list of ids or a list of blocks
+	ListAttribute string `json:"list_attribute"`
+
+	// The attribute of a block of ListAttribute that holds the id, empty if ListAttribute is a list of ids
+	IdAttribute string `json:"id_attribute,omitempty"`
+
+	// The other attributes of the blocks, they may be arguments of the resource
+	BlockAttributes []string `json:"block_attributes,omitempty"`
+}
+
+// The data sources that can list the ids of a resource, sorted by name
+func fromTerraformListDataSources(provider shim.Provider) []*TerraformListDataSourceIR {
+	listDataSources := make([]*TerraformListDataSourceIR, 0)
+	provider.DataSourcesMap().Range(func(dataSourceName string, dataSource shim.Resource) bool {
+		if listDataSource := FromTerraformListDataSource(dataSourceName, dataSource); listDataSource != nil {
+			listDataSources = append(listDataSources, listDataSource)
+		}
+		return true
+	})
+	sort.Slice(listDataSources, func(i, j int) bool {
+		return listDataSources[i].DataSourceName < listDataSources[j].DataSourceName
+	})
+	return listDataSources
+}
+
+// FromTerraformListDataSource How the data source lists ids, nil if it has a required argument or no computed list of
+// ids or of blocks with an id
+func FromTerraformListDataSource(dataSourceName string, dataSource shim.Resource) *TerraformListDataSourceIR {
+	hasRequiredArgument := false
+	idLists := make(map[string]struct{})
+	var blockList *TerraformListDataSourceIR
+	dataSource.Schema().Range(func(name string, attribute shim.Schema) bool {
+		if attribute.Required() {
+			hasRequiredArgument = true
+			return false
+		}
+		if !attribute.Computed() || (attribute.Type() != shim.TypeList && attribute.Type() != shim.TypeSet) {
+			return true
+		}
+		switch elem := attribute.Elem().(type) {
+		case shim.Schema:
+			if elem.Type() == shim.TypeString {
+				idLists[name] = struct{}{}
+			}
+		case shim.Resource:
+			if _, hasId := elem.Schema().GetOk("id"); !hasId {
+				return true
+			}
+			candidate := &TerraformListDataSourceIR{DataSourceName: dataSourceName, ListAttribute: name, IdAttribute: "id"}
+			elem.Schema().Range(func(blockAttributeName string, _ shim.Schema) bool {
+				if blockAttributeName != "id" {
+					candidate.BlockAttributes = append(candidate.BlockAttributes, blockAttributeName)
+				}
+				return true
+			})
+			sort.Strings(candidate.BlockAttributes)
+			// Several lists of blocks, the first by name wins so that the IR does not change between runs
+			if blockList == nil || candidate.ListAttribute < blockList.ListAttribute {
+				blockList = candidate
+			}
+		}
+		return true
+	})
+	if hasRequiredArgument {
+		return nil
+	}
+	// ids is surely what the name says, names are the ids of some resources only
+	if _, ok := idLists["ids"]; ok {
+		return &TerraformListDataSourceIR{DataSourceName: dataSourceName, ListAttribute: "ids"}
+	}
+	if blockList != nil {
+		return blockList
+	}
+	if _, ok := idLists["names"]; ok {
+		return &TerraformListDataSourceIR{DataSourceName: dataSourceName, ListAttribute: "names"}
+	}
+	return nil
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// How sure the analyzer is that a data source lists the ids of a resource, written next to the generated lister
+const (
+	ListerConfidenceHigh   = "high"
+	ListerConfidenceMedium = "medium"
+	ListerConfidenceLow    = "low"
+)
+
+// ResourceLister A data source the ListResourceParamsFunc of a resource lists the resource with
+type ResourceLister struct {
+	ResourceName string
+	DataSource   *TerraformListDataSourceIR
+
+	// One of the ListerConfidence* constants
+	Confidence string
+
+	// Why the data source was paired with the resource and why the confidence is not higher
+	Reasons []string
+
+	// The required arguments of the resource the blocks of the data source have, they are passed with the id
+	Arguments []string
+}
+
+// PairResourceListers The data source every resource of the IR can be listed with, by the name of the resource, a
+// resource no data source was found for is not in it
+func PairResourceListers(terraformSchemaIR *TerraformProviderSchemaIR) map[string]*ResourceLister {
+	dataSources := make(map[string]*TerraformListDataSourceIR, len(terraformSchemaIR.ListDataSources))
+	for _, dataSource := range terraformSchemaIR.ListDataSources {
+		dataSources[dataSource.DataSourceName] = dataSource
+	}
+	listers := make(map[string]*ResourceLister)
+	for _, resource := range terraformSchemaIR.Resources {
+		if lister := pairResourceLister(resource, dataSources); lister != nil {
+			listers[resource.ResourceName] = lister
+		}
+	}
+	return listers
+}
+
+func pairResourceLister(resource *TerraformResourceSchemaIR, dataSources map[string]*TerraformListDataSourceIR) *ResourceLister {
+	// The plural of the resource, aws_vpc and aws_vpcs, is more likely to list it than a data source of its ids
+	confidenceLevel := 0
+	dataSource := dataSources[pluralize(resource.ResourceName)]
+	lister := &ResourceLister{ResourceName: resource.ResourceName, DataSource: dataSource}
+	if dataSource != nil {
+		lister.Reasons = append(lister.Reasons, fmt.Sprintf("%s is the plural of the resource", dataSource.DataSourceName))
+	} else if dataSource = dataSources[resource.ResourceName+"_ids"]; dataSource != nil {
+		confidenceLevel++
+		lister.DataSource = dataSource
+		lister.Reasons = append(lister.Reasons, fmt.Sprintf("%s is named after the ids of the resource", dataSource.DataSourceName))
+	} else {
+		return nil
+	}
+
+	if dataSource.IdAttribute == "" && dataSource.ListAttribute != "ids" {
+		confidenceLevel++
+		lister.Reasons = append(lister.Reasons, fmt.Sprintf("it lists %s, they are taken as the ids", dataSource.ListAttribute))
+	}
+
+	// The arguments the resource requires may be needed to read it, the blocks of the data source may have them
+	blockAttributes := make(map[string]struct{}, len(dataSource.BlockAttributes))
+	for _, name := range dataSource.BlockAttributes {
+		blockAttributes[name] = struct{}{}
+	}
+	missingArguments := make([]string, 0)
+	for _, column := range resource.Columns {
+		if !column.Required || column.IsID() {
+			continue
+		}
+		if _, ok := blockAttributes[column.ColumnName]; ok {
+			lister.Arguments = append(lister.Arguments, column.ColumnName)
+		} else {
+			missingArguments = append(missingArguments, column.ColumnName)
+		}
+	}
+	sort.Strings(lister.Arguments)
+	if len(missingArguments) != 0 {
+		sort.Strings(missingArguments)
+		confidenceLevel++
+		lister.Reasons = append(lister.Reasons, fmt.Sprintf("it does not give the required arguments %s of the resource", strings.Join(missingArguments, ", ")))
+	}
+
+	switch {
+	case confidenceLevel == 0:
+		lister.Confidence = ListerConfidenceHigh
+	case confidenceLevel == 1:
+		lister.Confidence = ListerConfidenceMedium
+	default:
+		lister.Confidence = ListerConfidenceLow
+	}
+	return lister
+}
+
+// The plural of the last word of a snake case name, aws_vpc to aws_vpcs, aws_iam_policy to aws_iam_policies
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	default:
+		return name + "s"
+	}
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// The body of the ListResourceParamsFunc of the resource, it calls the data source through the bridge of the client
+func (x *ResourceLister) buildListResourceParamsBody() string {
+	buff := strings.Builder{}
+	buff.WriteString(fmt.Sprintf("\t\t\t// Listed with the data source %s, confidence %s: %s\n", x.DataSource.DataSourceName, x.Confidence, strings.Join(x.Reasons, ", ")))
+	if x.Confidence != ListerConfidenceHigh {
+		buff.WriteString("\t\t\t// Check that the data source lists the resources this table needs before relying on it\n")
+	}
+	arguments := []string{"ctx", "clientMeta", "taskClient", fmt.Sprintf("%q", x.DataSource.DataSourceName), fmt.Sprintf("%q", x.DataSource.ListAttribute), fmt.Sprintf("%q", x.DataSource.IdAttribute)}
+	for _, argument := range x.Arguments {
+		arguments = append(arguments, fmt.Sprintf("%q", argument))
+	}
+	buff.WriteString(fmt.Sprintf("\t\t\treturn listResourceParamsByDataSource(%s)", strings.Join(arguments, ", ")))
+	return buff.String()
+}
@@ -0,0 +1,95 @@
+package generate_selefra_terraform_provider
+
+import (
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestListDataSourceProvider() shim.Provider {
+	stringColumn := (&shimschema.Schema{Type: shim.TypeString, Computed: true}).Shim()
+	stringList := (&shimschema.Schema{Type: shim.TypeList, Computed: true, Elem: (&shimschema.Schema{Type: shim.TypeString}).Shim()}).Shim()
+	return (&shimschema.Provider{
+		DataSourcesMap: shimschema.ResourceMap{
+			"test_vpcs": (&shimschema.Resource{Schema: shimschema.SchemaMap{
+				"ids":  stringList,
+				"tags": (&shimschema.Schema{Type: shim.TypeMap, Optional: true}).Shim(),
+			}}).Shim(),
+			"test_containers": (&shimschema.Resource{Schema: shimschema.SchemaMap{
+				"containers": (&shimschema.Schema{
+					Type:     shim.TypeList,
+					Computed: true,
+					Elem: (&shimschema.Resource{Schema: shimschema.SchemaMap{
+						"id":      stringColumn,
+						"name":    stringColumn,
+						"account": stringColumn,
+					}}).Shim(),
+				}).Shim(),
+			}}).Shim(),
+			"test_roles": (&shimschema.Resource{Schema: shimschema.SchemaMap{"names": stringList}}).Shim(),
+			// It can not be read without an argument
+			"test_subnets": (&shimschema.Resource{Schema: shimschema.SchemaMap{
+				"vpc_id": (&shimschema.Schema{Type: shim.TypeString, Required: true}).Shim(),
+				"ids":    stringList,
+			}}).Shim(),
+			// It does not list anything
+			"test_vpc": (&shimschema.Resource{Schema: shimschema.SchemaMap{"id": stringColumn}}).Shim(),
+		},
+	}).Shim()
+}
+
+func TestFromTerraformListDataSources(t *testing.T) {
+	assert.Equal(t, []*TerraformListDataSourceIR{
+		{DataSourceName: "test_containers", ListAttribute: "containers", IdAttribute: "id", BlockAttributes: []string{"account", "name"}},
+		{DataSourceName: "test_roles", ListAttribute: "names"},
+		{DataSourceName: "test_vpcs", ListAttribute: "ids"},
+	}, fromTerraformListDataSources(newTestListDataSourceProvider()))
+}
+
+func TestPluralize(t *testing.T) {
+	assert.Equal(t, "aws_vpcs", pluralize("aws_vpc"))
+	assert.Equal(t, "aws_iam_policies", pluralize("aws_iam_policy"))
+	assert.Equal(t, "aws_api_gateway_keys", pluralize("aws_api_gateway_key"))
+	assert.Equal(t, "aws_ec2_hosts", pluralize("aws_ec2_host"))
+	assert.Equal(t, "aws_elasticache_clusters", pluralize("aws_elasticache_cluster"))
+	assert.Equal(t, "test_addresses", pluralize("test_address"))
+	assert.Equal(t, "test_boxes", pluralize("test_box"))
+	assert.Equal(t, "test_branches", pluralize("test_branch"))
+}
+
+func TestPairResourceListers(t *testing.T) {
+	ir := &TerraformProviderSchemaIR{
+		Resources: []*TerraformResourceSchemaIR{
+			{ResourceName: "test_vpc", Columns: []*TerraformColumnSchemaIR{{ColumnName: "id"}, {ColumnName: "cidr_block", Required: true}}},
+			{ResourceName: "test_container", Columns: []*TerraformColumnSchemaIR{{ColumnName: "id"}, {ColumnName: "account", Required: true}}},
+			{ResourceName: "test_role", Columns: []*TerraformColumnSchemaIR{{ColumnName: "id"}}},
+			{ResourceName: "test_image", Columns: []*TerraformColumnSchemaIR{{ColumnName: "id"}}},
+			{ResourceName: "test_bucket", Columns: []*TerraformColumnSchemaIR{{ColumnName: "id"}}},
+		},
+		ListDataSources: []*TerraformListDataSourceIR{
+			{DataSourceName: "test_containers", ListAttribute: "containers", IdAttribute: "id", BlockAttributes: []string{"account", "name"}},
+			{DataSourceName: "test_image_ids", ListAttribute: "ids"},
+			{DataSourceName: "test_roles", ListAttribute: "names"},
+			{DataSourceName: "test_vpcs", ListAttribute: "ids"},
+		},
+	}
+	listers := PairResourceListers(ir)
+	assert.Len(t, listers, 4)
+	assert.Nil(t, listers["test_bucket"])
+
+	// The blocks give the required argument
+	assert.Equal(t, ListerConfidenceHigh, listers["test_container"].Confidence)
+	assert.Equal(t, []string{"account"}, listers["test_container"].Arguments)
+
+	assert.Equal(t, ListerConfidenceMedium, listers["test_vpc"].Confidence)
+	assert.Contains(t, listers["test_vpc"].Reasons, "it does not give the required arguments cidr_block of the resource")
+	assert.Equal(t, ListerConfidenceMedium, listers["test_role"].Confidence)
+	assert.Equal(t, ListerConfidenceMedium, listers["test_image"].Confidence)
+
+	assert.Equal(t, `			// Listed with the data source test_containers, confidence high: test_containers is the plural of the resource
+			return listResourceParamsByDataSource(ctx, clientMeta, taskClient, "test_containers", "containers", "id", "account")`, listers["test_container"].buildListResourceParamsBody())
+	assert.Equal(t, `			// Listed with the data source test_roles, confidence medium: test_roles is the plural of the resource, it lists names, they are taken as the ids
+			// Check that the data source lists the resources this table needs before relying on it
+			return listResourceParamsByDataSource(ctx, clientMeta, taskClient, "test_roles", "names", "")`, listers["test_role"].buildListResourceParamsBody())
+}
@@ -8,14 +8,19 @@ import (
 	"github.com/selefra/selefra-provider-sdk/provider/schema"
 	"github.com/selefra/selefra-provider-sdk/terraform/bridge"
 	"github.com/selefra/selefra-provider-sdk/terraform/provider"
-	"github.com/yezihack/colorlog"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 )
 
 type SchemaIRManager struct {
 	config *Config
+
+	// The directories a dry run downloaded the provider into
+	temporaryDirectories []string
 }
 
 func NewSchemaIRManager(config *Config) *SchemaIRManager {
@@ -25,118 +30,201 @@ func NewSchemaIRManager(config *Config) *SchemaIRManager {
 }
 
 func (x *SchemaIRManager) GenerateIRAndSave(ctx context.Context) error {
-	colorlog.Info("begin generate terraform schema IR...")
+	x.config.GetLogger().Info("begin generate terraform schema IR...")
 	terraformProviderSchemaIR, err := x.GenTerraformProviderSchemaIR(ctx)
 	if err != nil {
-		colorlog.Error("generate terraform schema IR error: %s", err.Error())
+		x.config.GetLogger().Error("generate terraform schema IR error: %s", err.Error())
 		return err
 	}
-	colorlog.Info("generate terraform schema IR success, begin save to %s", x.getTerraformSchemaIRSavePath())
+	x.config.GetLogger().Info("generate terraform schema IR success, begin save to %s", x.getTerraformSchemaIRSavePath())
 	err = x.saveTerraformSchemaIR(terraformProviderSchemaIR)
 	if err != nil {
-		colorlog.Error("save terraform schema IR to %s error: %s", x.getTerraformSchemaIRSavePath(), err.Error())
+		x.config.GetLogger().Error("save terraform schema IR to %s error: %s", x.getTerraformSchemaIRSavePath(), err.Error())
 		return err
 	}
-	colorlog.Info("save terraform schema IR to %s success", x.getTerraformSchemaIRSavePath())
+	x.config.GetLogger().Info("save terraform schema IR to %s success", x.getTerraformSchemaIRSavePath())
 	return nil
 }
 
 func (x *SchemaIRManager) ReadOrGenerateSchemaIR(ctx context.Context) (*TerraformProviderSchemaIR, error) {
-	colorlog.Info("begin read or generate terraform schema IR...")
+	x.config.GetLogger().Info("begin read or generate terraform schema IR...")
 	ir, err := x.readTerraformSchemaIR()
 	if ir == nil {
-		colorlog.Info("not found before schema.json, so generate it...")
+		x.config.GetLogger().Info("not found before schema.json, so generate it...")
 		err := x.GenerateIRAndSave(ctx)
 		if err != nil {
-			colorlog.Error("generate terraform schema IR error: %s", err.Error())
+			x.config.GetLogger().Error("generate terraform schema IR error: %s", err.Error())
 			return nil, err
 		}
 	}
 	schemaIR, err := x.readTerraformSchemaIR()
 	if err != nil {
-		colorlog.Error("read terraform schema IR error: %s", err.Error())
+		x.config.GetLogger().Error("read terraform schema IR error: %s", err.Error())
 		return nil, err
 	}
-	colorlog.Info("read terraform schema IR error success")
+	x.config.GetLogger().Info("read terraform schema IR error success")
 	return schemaIR, nil
 }
 
 func (x *SchemaIRManager) GenTerraformProviderSchemaIR(ctx context.Context) (*TerraformProviderSchemaIR, error) {
-	colorlog.Info("begin start terraform provider bridge for %s ...", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
+	x.config.GetLogger().Info("begin start terraform provider bridge for %s ...", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
+	// Removed once the terraform provider is shut down, the defers run in reverse order
+	defer x.removeTemporaryDirectories()
 	terraformProviderBridge, err := x.RunTerraformProvider(ctx)
 	if err != nil {
-		colorlog.Error("start terraform provider bridge for %s error: %s", x.config.Terraform.TerraformProvider.GetOrParseProviderName(), err.Error())
+		x.config.GetLogger().Error("start terraform provider bridge for %s error: %s", x.config.Terraform.TerraformProvider.GetOrParseProviderName(), err.Error())
 		return nil, err
 	}
-	colorlog.Info("start terraform provider bridge %s success", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
+	x.config.GetLogger().Info("start terraform provider bridge %s success", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
 	defer func() {
 		err := terraformProviderBridge.Shutdown()
 		if err != nil {
-			colorlog.Error("terraform provider bridge %s shutdown failed: %s", x.config.Terraform.TerraformProvider.GetOrParseProviderName(), err.Error())
+			x.config.GetLogger().Error("terraform provider bridge %s shutdown failed: %s", x.config.Terraform.TerraformProvider.GetOrParseProviderName(), err.Error())
 		} else {
-			colorlog.Info("terraform provider bridge %s shutdown success", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
+			x.config.GetLogger().Info("terraform provider bridge %s shutdown success", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
 		}
 	}()
 	return FromTerraformProviderSchema(x.config.Terraform.TerraformProvider.GetOrParseProviderName(), terraformProviderBridge.GetProvider(), x.config), nil
 }
 
+// The download directories of a dry run are removed with everything the provider wrote into them
+func (x *SchemaIRManager) removeTemporaryDirectories() {
+	for _, directory := range x.temporaryDirectories {
+		if err := os.RemoveAll(directory); err != nil {
+			x.config.GetLogger().Error("remove temporary directory %s error: %s", directory, err.Error())
+		}
+	}
+	x.temporaryDirectories = nil
+}
+
 func (x *SchemaIRManager) RunTerraformProvider(ctx context.Context) (*bridge.TerraformBridge, error) {
-	providerExecFileSaveDirectory := filepath.Join(x.config.Output.Directory, "/bin/", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
-	colorlog.Info("begin download provider %s's exec file to %s", x.config.Terraform.TerraformProvider.GetOrParseProviderName(), providerExecFileSaveDirectory)
-	providerExecFilePath, err := provider.NewProviderDownloader(x.config.Terraform.TerraformProvider.ExecuteFiles).Download(providerExecFileSaveDirectory)
+	providerExecFileSaveDirectory := filepath.Join(x.config.GetDownloadDirectory(), x.config.Terraform.TerraformProvider.GetOrParseProviderName())
+	// The executable has to be on the disk to run, a dry run that does not find it downloaded already downloads it
+	// outside of the project
+	if x.config.IsDryRun() && !isProviderExecFileDownloaded(x.config.Terraform.TerraformProvider.ExecuteFiles, providerExecFileSaveDirectory) {
+		temporaryDirectory, err := os.MkdirTemp("", "selefra-terraform-provider-")
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDownload, err.Error())
+		}
+		x.temporaryDirectories = append(x.temporaryDirectories, temporaryDirectory)
+		providerExecFileSaveDirectory = temporaryDirectory
+	}
+	x.config.GetLogger().Info("begin download provider %s's exec file to %s", x.config.Terraform.TerraformProvider.GetOrParseProviderName(), providerExecFileSaveDirectory)
+	httpClient, err := x.config.GetHttpClient()
 	if err != nil {
-		colorlog.Error("download provider %s's exec file failed: %s", x.config.Terraform.TerraformProvider.GetOrParseProviderName(), err.Error())
 		return nil, err
 	}
+	providerExecFilePath, err := downloadProviderExecFile(ctx, httpClient, x.config.Terraform.TerraformProvider.ExecuteFiles, providerExecFileSaveDirectory)
+	if err != nil {
+		x.config.GetLogger().Error("download provider %s's exec file failed: %s", x.config.Terraform.TerraformProvider.GetOrParseProviderName(), err.Error())
+		return nil, fmt.Errorf("%w: %s", ErrDownload, err.Error())
+	}
+	// No file matches the platform the scaffold runs on
+	if providerExecFilePath == "" {
+		return nil, fmt.Errorf("%w: no execute file of provider %s for %s/%s", ErrDownload, x.config.Terraform.TerraformProvider.GetOrParseProviderName(), runtime.GOOS, runtime.GOARCH)
+	}
 	terraformProviderBridge := bridge.NewTerraformBridge(providerExecFilePath)
-	// Some providers need to configure parameters at startup
-	providerConfig := make(map[string]any, 0)
-	if x.config.Terraform.TerraformProvider.Config != "" {
-		err := json.Unmarshal([]byte(x.config.Terraform.TerraformProvider.Config), &providerConfig)
-		if err != nil {
-			colorlog.Error("json unmarshal provider config error, raw = %s, err msg = %s", x.config.Terraform.TerraformProvider.Config, err.Error())
-			return nil, fmt.Errorf("json unmarshal terraform provider config error: %+v", err)
-		}
+	// Some providers need to configure parameters at startup, the values are not logged because they may be secrets
+	providerConfig, err := x.config.Terraform.TerraformProvider.ResolveProviderConfig()
+	if err != nil {
+		x.config.GetLogger().Error("resolve provider config error: %s", err.Error())
+		return nil, fmt.Errorf("%w: resolve terraform.provider.config error: %s", ErrCheckConfigFailed, err.Error())
 	}
-	colorlog.Info("begin run bridge for provider %s...", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
+	x.config.GetLogger().Info("begin run bridge for provider %s...", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
 	err = terraformProviderBridge.StartBridge(ctx, providerConfig)
 	if err != nil {
-		colorlog.Error("run bridge for provider %s failed: %s", x.config.Terraform.TerraformProvider.GetOrParseProviderName(), err.Error())
-		return nil, err
+		x.config.GetLogger().Error("run bridge for provider %s failed: %s", x.config.Terraform.TerraformProvider.GetOrParseProviderName(), err.Error())
+		return nil, fmt.Errorf("%w: %s", ErrBridgeStart, err.Error())
 	}
-	colorlog.Info("run bridge for provider %s success", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
+	x.config.GetLogger().Info("run bridge for provider %s success", x.config.Terraform.TerraformProvider.GetOrParseProviderName())
 	return terraformProviderBridge, nil
 }
 
+// The directories that are being downloaded into, a download directory may be shared by the providers generated at once
+var providerDownloadLocks sync.Map
+
+// Only one download into the same directory runs at a time, the ones that wait find the file already downloaded. The
+// file of the platform the scaffold runs on is downloaded into <directory>/<provider name>/<version>, empty is returned
+// when there is none
+func downloadProviderExecFile(ctx context.Context, httpClient *HttpClient, files []*provider.TerraformProviderFile, directory string) (string, error) {
+	lock, _ := providerDownloadLocks.LoadOrStore(filepath.Clean(directory), &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	file := findPlatformProviderFile(files)
+	if file == nil {
+		return "", nil
+	}
+	providerDownloadDirectory := filepath.Join(directory, file.ProviderName, file.ProviderVersion)
+	if executable := findProviderExecFile(providerDownloadDirectory); executable != "" {
+		return executable, nil
+	}
+	if err := httpClient.Download(ctx, file.DownloadUrl, file.Sha256Sum, providerDownloadDirectory); err != nil {
+		return "", err
+	}
+	if executable := findProviderExecFile(providerDownloadDirectory); executable != "" {
+		return executable, nil
+	}
+	return "", fmt.Errorf("no terraform-provider-* executable is found in %s downloaded from %s", providerDownloadDirectory, file.DownloadUrl)
+}
+
+// The file of the platform the scaffold runs on, nil if there is none
+func findPlatformProviderFile(files []*provider.TerraformProviderFile) *provider.TerraformProviderFile {
+	for _, file := range files {
+		if file.OS == runtime.GOOS && file.Arch == runtime.GOARCH {
+			return file
+		}
+	}
+	return nil
+}
+
+// Whether downloadProviderExecFile would find the executable in the directory without downloading it
+func isProviderExecFileDownloaded(files []*provider.TerraformProviderFile, directory string) bool {
+	file := findPlatformProviderFile(files)
+	return file != nil && findProviderExecFile(filepath.Join(directory, file.ProviderName, file.ProviderVersion)) != ""
+}
+
+// The executable of the provider in the download directory, empty if it is not there
+func findProviderExecFile(providerDownloadDirectory string) string {
+	executable := ""
+	_ = filepath.Walk(providerDownloadDirectory, func(path string, info fs.FileInfo, err error) error {
+		if err == nil && executable == "" && !info.IsDir() && strings.HasPrefix(info.Name(), "terraform-provider-") {
+			executable = path
+		}
+		return nil
+	})
+	return executable
+}
+
 func (x *SchemaIRManager) getTerraformSchemaIRSavePath() string {
 	schemaJsonOutputDirectory := filepath.Join(x.config.Output.Directory, "/provider")
-	_ = os.MkdirAll(schemaJsonOutputDirectory, os.ModePerm)
+	_ = x.config.GetFileSystem().MkdirAll(schemaJsonOutputDirectory, os.ModePerm)
 	return filepath.Join(schemaJsonOutputDirectory, "/schema.json")
 }
 
 func (x *SchemaIRManager) saveTerraformSchemaIR(terraformProviderSchemaIR *TerraformProviderSchemaIR) error {
 	marshal, err := json.Marshal(terraformProviderSchemaIR)
 	if err != nil {
-		colorlog.Error("save terraform schema IR failed: %s", err.Error())
+		x.config.GetLogger().Error("save terraform schema IR failed: %s", err.Error())
 		return err
 	}
-	if err := os.WriteFile(x.getTerraformSchemaIRSavePath(), marshal, os.ModePerm); err != nil {
-		colorlog.Error("save terraform schema IR failed: %s", err.Error())
+	if err := x.config.GetFileSystem().WriteFile(x.getTerraformSchemaIRSavePath(), marshal, os.ModePerm); err != nil {
+		x.config.GetLogger().Error("save terraform schema IR failed: %s", err.Error())
 		return err
 	}
 	return nil
 }
 
 func (x *SchemaIRManager) readTerraformSchemaIR() (*TerraformProviderSchemaIR, error) {
-	schemaBytes, err := os.ReadFile(x.getTerraformSchemaIRSavePath())
+	schemaBytes, err := x.config.GetFileSystem().ReadFile(x.getTerraformSchemaIRSavePath())
 	if err != nil {
-		colorlog.Error("read terraform schema IR failed: %s", err.Error())
+		x.config.GetLogger().Error("read terraform schema IR failed: %s", err.Error())
 		return nil, err
 	}
 	terraformProviderSchemaIR := &TerraformProviderSchemaIR{}
 	err = json.Unmarshal(schemaBytes, &terraformProviderSchemaIR)
 	if err != nil {
-		colorlog.Error("read terraform schema IR failed: %s", err.Error())
+		x.config.GetLogger().Error("read terraform schema IR failed: %s", err.Error())
 		return nil, err
 	}
 	return terraformProviderSchemaIR, nil
@@ -147,21 +235,50 @@ func (x *SchemaIRManager) readTerraformSchemaIR() (*TerraformProviderSchemaIR, e
 type TerraformProviderSchemaIR struct {
 	ProviderName string                       `json:"provider_name"`
 	Resources    []*TerraformResourceSchemaIR `json:"resources"`
+
+	// The resources of the provider that were left out of the IR and why, the ones not selected by the config are not listed
+	SkippedResources []*SkippedResource `json:"skipped_resources,omitempty"`
+
+	// Every resource and data source of the provider whatever the config selects, see list-resources
+	Catalog []*TerraformResourceSummaryIR `json:"catalog,omitempty"`
+
+	// The arguments of the provider block, what the generated selefra provider is configured with
+	ProviderConfig []*TerraformProviderConfigArgumentIR `json:"provider_config,omitempty"`
+
+	// The data sources that list the ids of some resource, init pairs them with the resources, see PairResourceListers
+	ListDataSources []*TerraformListDataSourceIR `json:"list_data_sources,omitempty"`
 }
 
+// SkippedResource A terraform resource no table is generated for
+type SkippedResource struct {
+	ResourceName string `json:"resource_name"`
+	Reason       string `json:"reason"`
+}
+
+// The reason of the resources that have no id column, a table needs it as its primary key
+const skippedReasonNoIdColumn = "it has no id column"
+
 func FromTerraformProviderSchema(terraformProviderName string, provider shim.Provider, config *Config) *TerraformProviderSchemaIR {
 	terraformProviderSchemaIR := &TerraformProviderSchemaIR{
-		ProviderName: terraformProviderName,
+		ProviderName:    terraformProviderName,
+		Catalog:         fromTerraformProviderCatalog(provider),
+		ProviderConfig:  FromTerraformProviderConfigSchema(provider.Schema()),
+		ListDataSources: fromTerraformListDataSources(provider),
 	}
 	provider.ResourcesMap().Range(func(terraformResourceName string, terraformResourceSchema shim.Resource) bool {
 
 		if !config.IsResourceNeedGenerate(terraformResourceName) {
-			colorlog.Info("terraform resource %s, do not need generate, so ignored", terraformResourceName)
+			config.GetLogger().Info("terraform resource %s, do not need generate, so ignored", terraformResourceName)
 			return true
 		}
 
 		resourceSchemaIR := FromTerraformResourceSchema(terraformResourceName, terraformResourceSchema, config)
 		if resourceSchemaIR == nil {
+			config.GetLogger().Warn("terraform resource %s is skipped, %s", terraformResourceName, skippedReasonNoIdColumn)
+			terraformProviderSchemaIR.SkippedResources = append(terraformProviderSchemaIR.SkippedResources, &SkippedResource{
+				ResourceName: terraformResourceName,
+				Reason:       skippedReasonNoIdColumn,
+			})
 			return true
 		}
 		terraformProviderSchemaIR.Resources = append(terraformProviderSchemaIR.Resources, resourceSchemaIR)
@@ -232,7 +349,7 @@ func (x *TerraformResourceSchemaIR) ToSelefraTableRenderParams(selefraModuleName
 	tableParams := &SelefraTableSchemaRenderParams{
 		TableSchemaGeneratorName: x.BuildTableSchemaGeneratorName(),
 		TableName:                x.ResourceName,
-		Description:              processDescription(x.Description),
+		Description:              x.Description,
 		PrimaryKeys:              []string{"id"},
 		ModuleName:               selefraModuleName,
 	}
@@ -246,15 +363,15 @@ func (x *TerraformResourceSchemaIR) ToSelefraTableRenderParams(selefraModuleName
 		}
 		tableParams.MergeColumnRenderParamsImport(renderParams)
 	}
+	// The resource ends up in the skipped resources of the generate summary
 	if !hasIdColumn {
-		colorlog.Error("terraform resource %s do not have id column, so ignored", x.ResourceName)
 		return nil
 	}
 
 	// Add an additional column to store the original response data
 	tableParams.ColumnSchemaSlice = append(tableParams.ColumnSchemaSlice, &SelefraColumnSchemaRenderParams{
 		ColumnName:                "selefra_terraform_original_result",
-		Description:               "`save terraform original result for compatibility`",
+		Description:               "save terraform original result for compatibility",
 		ColumnTypeCodeString:      "schema.ColumnTypeJSON",
 		ExtractorInlineCodeString: "column_value_extractor.TerraformRawDataColumnValueExtractor()",
 	})
@@ -263,9 +380,7 @@ func (x *TerraformResourceSchemaIR) ToSelefraTableRenderParams(selefraModuleName
 }
 
 func (x *TerraformResourceSchemaIR) BuildTableSchemaGeneratorName() string {
-	s := strings.Replace(x.ResourceName, "_", " ", -1)
-	s = strings.Title(s)
-	return strings.Replace(s, " ", "", -1) + "SchemaGenerator"
+	return toPascalCase(x.ResourceName) + "SchemaGenerator"
 }
 
 // ------------------------------------------------- --------------------------------------------------------------------
@@ -274,6 +389,9 @@ type TerraformColumnSchemaIR struct {
 	ColumnName  string            `json:"column_name"`
 	ColumnType  schema.ColumnType `json:"column_type"`
 	Description string            `json:"description"`
+
+	// Whether the column is a required argument of the resource
+	Required bool `json:"required,omitempty"`
 }
 
 // FromTerraformColumnSchema Generates intermediate structure information from the column structure of the terraform
@@ -281,6 +399,7 @@ func FromTerraformColumnSchema(terraformColumnName string, terraformColumnSchema
 	columnSchema := &TerraformColumnSchemaIR{
 		ColumnName:  terraformColumnName,
 		Description: terraformColumnSchema.Description(),
+		Required:    terraformColumnSchema.Required(),
 	}
 
 	// column's type & column value extractor
@@ -307,7 +426,7 @@ func (x *TerraformColumnSchemaIR) ToSelefraSchemaRenderParams() *SelefraColumnSc
 
 	selefraColumnRenderParams := &SelefraColumnSchemaRenderParams{
 		ColumnName:  x.ColumnName,
-		Description: processDescription(x.Description),
+		Description: x.Description,
 	}
 
 	// column's type & column value extractor
@@ -323,7 +442,7 @@ func (x *TerraformColumnSchemaIR) ToSelefraSchemaRenderParams() *SelefraColumnSc
 	case schema.ColumnTypeJSON:
 		// All are converted to JSON
 		selefraColumnRenderParams.ColumnTypeCodeString = "schema.ColumnTypeJSON"
-		selefraColumnRenderParams.ExtractorInlineCodeString = fmt.Sprintf("column_value_extractor.TerraformRawDataColumnValueExtractor(\"%s\")", x.ColumnName)
+		selefraColumnRenderParams.ExtractorInlineCodeString = fmt.Sprintf("column_value_extractor.TerraformRawDataColumnValueExtractor(%q)", x.ColumnName)
 		selefraColumnRenderParams.AddDependencyImport("github.com/selefra/selefra-provider-sdk/terraform/column_value_extractor")
 	case schema.ColumnTypeNotAssign:
 		selefraColumnRenderParams.ColumnTypeCodeString = "schema.ColumnTypeInvalid"
@@ -335,5 +454,3 @@ func (x *TerraformColumnSchemaIR) ToSelefraSchemaRenderParams() *SelefraColumnSc
 func (x *TerraformColumnSchemaIR) IsID() bool {
 	return strings.ToLower(x.ColumnName) == "id"
 }
-
-// ------------------------------------------------- --------------------------------------------------------------------
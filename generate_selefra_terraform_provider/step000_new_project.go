@@ -0,0 +1,312 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NewProjectOptions The answers given up front, the wizard only asks for the ones that are not set
+type NewProjectOptions struct {
+
+	// The repository of the terraform provider, the registry is searched when it is not set
+	TerraformProviderUrl string
+
+	// What to search the registry for, asked when neither it nor the provider url is set
+	SearchQuery string
+
+	// The version of the terraform provider, defaults to the latest one
+	TerraformProviderVersion string
+
+	// The go module name of the new selefra provider
+	ModuleName string
+
+	// The directory the project is created in, defaults to ./selefra-provider-<name>
+	OutputDirectory string
+
+	// The resources to generate tables for, the wizard offers all of them when not set
+	Resources []string
+
+	// The Terraform registry to search, DefaultTerraformRegistryUrl if not set
+	RegistryUrl string
+
+	// How the registry and the releases of the provider are reached, it is written to config.yml when set
+	Network Network
+
+	// The logger of the wizard and the project config, the default logger if not set
+	Logger Logger
+
+	// The project is written through it, the disk if not set
+	FileSystem FileSystem
+}
+
+// NewProjectConfigRenderParams Parameters needed to render config.yml of a new project
+type NewProjectConfigRenderParams struct {
+	ModuleName string
+	RepoUrl    string
+	Version    string
+	Resources  []string
+	Network    *Network
+}
+
+// How many providers a registry search offers to choose from
+const newProjectSearchLimit = 10
+
+// ProjectWizard Bootstraps a selefra provider project from nothing: asks which terraform provider to convert, writes
+// config.yml and go.mod, then runs init
+type ProjectWizard struct {
+	options  *NewProjectOptions
+	prompter *Prompter
+	registry *TerraformRegistry
+	summary  *GenerateSummary
+}
+
+func NewProjectWizard(options *NewProjectOptions, prompter *Prompter) *ProjectWizard {
+	return &ProjectWizard{
+		options:  options,
+		prompter: prompter,
+		registry: NewTerraformRegistry(options.RegistryUrl),
+	}
+}
+
+// GetSummary The resources the project was initialized with, nil if the wizard did not get that far
+func (x *ProjectWizard) GetSummary() *GenerateSummary {
+	return x.summary
+}
+
+func (x *ProjectWizard) Run(ctx context.Context) (*Config, error) {
+
+	config := &Config{}
+	if x.options.Logger != nil {
+		config.SetLogger(x.options.Logger)
+	}
+	if x.options.FileSystem != nil {
+		config.SetFileSystem(x.options.FileSystem)
+	}
+
+	// config.yml is in the output directory, so a relative certificate file would no longer be found from there
+	config.Network = x.options.Network
+	if config.Network.CaFile != "" {
+		caFile, err := filepath.Abs(config.Network.CaFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Network.CaFile = caFile
+	}
+	httpClient, err := config.GetHttpClient()
+	if err != nil {
+		return nil, err
+	}
+	x.registry.SetHttpClient(httpClient)
+
+	registryProvider, err := x.askTerraformProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := x.askVersion(config, registryProvider); err != nil {
+		return nil, err
+	}
+	shortName := config.Terraform.TerraformProvider.ParseProviderShortName()
+	if config.Selefra.ModuleName, err = x.prompter.Ask("Go module name", x.getModuleNameOrDefault(shortName)); err != nil {
+		return nil, err
+	}
+	if config.Output.Directory, err = x.prompter.Ask("Output directory", x.getOutputDirectoryOrDefault(shortName)); err != nil {
+		return nil, err
+	}
+
+	// The configuration is built from the answers alone, a config.yml or cache in the working directory must not leak in
+	if err := checkConfig(config); err != nil {
+		return nil, err
+	}
+
+	// All resources are needed to offer them, the selection is applied to the IR afterwards
+	schemaIRManager := NewSchemaIRManager(config)
+	if err := schemaIRManager.GenerateIRAndSave(ctx); err != nil {
+		return nil, err
+	}
+	if err := x.askResources(config, schemaIRManager); err != nil {
+		return nil, err
+	}
+
+	if err := x.writeConfigYaml(config); err != nil {
+		return nil, err
+	}
+	if err := NewGoModGenerator(config).Run(); err != nil {
+		return nil, err
+	}
+	selefraTerraformProviderInit := NewSelefraTerraformProviderInit(config)
+	if err := selefraTerraformProviderInit.RewriteProject(); err != nil {
+		return nil, err
+	}
+	x.summary = selefraTerraformProviderInit.GetSummary()
+
+	config.GetLogger().Info("project %s is ready, run generate in %s to render the tables", config.Selefra.ModuleName, config.Output.Directory)
+	return config, nil
+}
+
+// The provider either comes from the given url or is picked from a registry search, the registry entry is nil for a url
+func (x *ProjectWizard) askTerraformProvider(config *Config) (*TerraformRegistryProvider, error) {
+	if x.options.TerraformProviderUrl != "" {
+		config.Terraform.TerraformProvider.RepoUrl = x.options.TerraformProviderUrl
+		return nil, nil
+	}
+
+	query := x.options.SearchQuery
+	for {
+		var err error
+		if query == "" {
+			if x.prompter.assumeYes {
+				return nil, fmt.Errorf("%w: either the provider url or a search query is required", ErrCheckConfigFailed)
+			}
+			if query, err = x.prompter.Ask("Search the terraform registry for a provider", ""); err != nil {
+				return nil, err
+			}
+			if query == "" {
+				continue
+			}
+		}
+
+		providers, err := x.registry.SearchProviders(query, newProjectSearchLimit)
+		if err != nil {
+			return nil, err
+		}
+		if len(providers) == 0 {
+			if x.prompter.assumeYes {
+				return nil, fmt.Errorf("%w: no provider in the terraform registry matches %s", ErrCheckConfigFailed, query)
+			}
+			config.GetLogger().Warn("no provider in the terraform registry matches %s", query)
+			query = ""
+			continue
+		}
+
+		choices := make([]string, 0, len(providers))
+		for _, registryProvider := range providers {
+			choices = append(choices, formatRegistryProvider(registryProvider))
+		}
+		index, err := x.prompter.Choose("Terraform provider", choices, 0)
+		if err != nil {
+			return nil, err
+		}
+		// The search results do not carry the versions
+		registryProvider, err := x.registry.GetProvider(providers[index].Namespace, providers[index].Name)
+		if err != nil {
+			return nil, err
+		}
+		config.Terraform.TerraformProvider.RepoUrl = registryProvider.GetRepoUrl()
+		return registryProvider, nil
+	}
+}
+
+func formatRegistryProvider(registryProvider *TerraformRegistryProvider) string {
+	s := fmt.Sprintf("%s (%s, %d downloads)", registryProvider.FullName(), registryProvider.Tier, registryProvider.Downloads)
+	if registryProvider.Description != "" {
+		s += " " + registryProvider.Description
+	}
+	return s
+}
+
+func (x *ProjectWizard) askVersion(config *Config, registryProvider *TerraformRegistryProvider) error {
+	defaultVersion := x.options.TerraformProviderVersion
+	if defaultVersion == "" && registryProvider != nil {
+		defaultVersion = registryProvider.Version
+	}
+	question := "Provider version"
+	if defaultVersion == "" {
+		question += ", empty for the latest release"
+	}
+	version, err := x.prompter.Ask(question, defaultVersion)
+	if err != nil {
+		return err
+	}
+	config.Terraform.TerraformProvider.Version = strings.TrimPrefix(version, "v")
+	return nil
+}
+
+func (x *ProjectWizard) getModuleNameOrDefault(shortName string) string {
+	if x.options.ModuleName != "" {
+		return x.options.ModuleName
+	}
+	return "github.com/selefra/selefra-provider-" + shortName
+}
+
+func (x *ProjectWizard) getOutputDirectoryOrDefault(shortName string) string {
+	if x.options.OutputDirectory != "" {
+		return x.options.OutputDirectory
+	}
+	return "./selefra-provider-" + shortName
+}
+
+// Narrow the IR down to the chosen resources, so that init and a later generate only see them
+func (x *ProjectWizard) askResources(config *Config, schemaIRManager *SchemaIRManager) error {
+	ir, err := schemaIRManager.readTerraformSchemaIR()
+	if err != nil {
+		return err
+	}
+	resourceNames := make([]string, 0, len(ir.Resources))
+	for _, resource := range ir.Resources {
+		resourceNames = append(resourceNames, resource.ResourceName)
+	}
+
+	selected := x.options.Resources
+	if len(selected) == 0 {
+		if selected, err = x.prompter.MultiSelect("Resources to generate tables for", resourceNames); err != nil {
+			return err
+		}
+	} else if selected, err = ParseSelection(strings.Join(selected, ","), resourceNames); err != nil {
+		return fmt.Errorf("%w: resources: %s", ErrCheckConfigFailed, err.Error())
+	}
+	// Selecting everything is the same as not listing any
+	if len(selected) == len(resourceNames) {
+		return nil
+	}
+
+	config.Terraform.TerraformProvider.Resources = selected
+	resources := make([]*TerraformResourceSchemaIR, 0, len(selected))
+	for _, resource := range ir.Resources {
+		if config.IsResourceNeedGenerate(resource.ResourceName) {
+			resources = append(resources, resource)
+		}
+	}
+	ir.Resources = resources
+	return schemaIRManager.saveTerraformSchemaIR(ir)
+}
+
+// config.yml is placed in the project, an existing one is only replaced when the user agrees
+func (x *ProjectWizard) writeConfigYaml(config *Config) error {
+	configPath := filepath.Join(config.Output.Directory, "config.yml")
+	if exists, err := config.GetFileSystem().Exists(configPath); err == nil && exists {
+		overwrite, err := x.prompter.Confirm(fmt.Sprintf("%s already exists, overwrite it?", configPath), false)
+		if err != nil {
+			return err
+		}
+		if !overwrite {
+			config.GetLogger().Warn("file %s already exists, so do not rewrite it", configPath)
+			return nil
+		}
+	}
+
+	t, err := config.LoadTemplate(NewProjectConfigTemplateName)
+	if err != nil {
+		return err
+	}
+	buffer := bytes.Buffer{}
+	params := &NewProjectConfigRenderParams{
+		ModuleName: config.Selefra.ModuleName,
+		RepoUrl:    config.Terraform.TerraformProvider.RepoUrl,
+		Version:    config.Terraform.TerraformProvider.Version,
+		Resources:  config.Terraform.TerraformProvider.Resources,
+		Network:    &config.Network,
+	}
+	if err := t.Execute(&buffer, params); err != nil {
+		return fmt.Errorf("%w: render %s error: %s", ErrTemplate, NewProjectConfigTemplateName, err.Error())
+	}
+	if err := config.GetFileSystem().WriteFile(configPath, buffer.Bytes(), os.ModePerm); err != nil {
+		config.GetLogger().Error("write file %s error: %s", configPath, err.Error())
+		return err
+	}
+	config.GetLogger().Info("write %s success", configPath)
+	return nil
+}
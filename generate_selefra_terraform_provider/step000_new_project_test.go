@@ -0,0 +1,79 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProjectWizard_writeConfigYaml(t *testing.T) {
+	directory := t.TempDir()
+	config := &Config{}
+	config.Selefra.ModuleName = "github.com/selefra/selefra-provider-foo"
+	config.Terraform.TerraformProvider.RepoUrl = "https://github.com/foo/terraform-provider-foo"
+	config.Terraform.TerraformProvider.Version = "1.2.3"
+	config.Terraform.TerraformProvider.Resources = []string{"foo_bar", "foo_baz"}
+	config.Output.Directory = directory
+	config.Network = Network{Proxy: "http://proxy.example.com:3128", NoProxy: []string{"example.com"}, Retries: -1}
+
+	wizard := NewProjectWizard(&NewProjectOptions{}, NewPrompter(strings.NewReader(""), &bytes.Buffer{}, true))
+	assert.Nil(t, wizard.writeConfigYaml(config))
+
+	// the written file is a valid configuration that holds the answers
+	configPath := filepath.Join(directory, "config.yml")
+	content, err := os.ReadFile(configPath)
+	assert.Nil(t, err)
+	problems, err := checkConfigKeys(configPath, content)
+	assert.Nil(t, err)
+	assert.Empty(t, problems)
+	readConfig, err := readConfigFromPath(configPath, NewNopLogger())
+	assert.Nil(t, err)
+	assert.Equal(t, config.Selefra.ModuleName, readConfig.Selefra.ModuleName)
+	assert.Equal(t, config.Terraform.TerraformProvider.RepoUrl, readConfig.Terraform.TerraformProvider.RepoUrl)
+	assert.Equal(t, "1.2.3", readConfig.Terraform.TerraformProvider.Version)
+	assert.Equal(t, []string{"foo_bar", "foo_baz"}, readConfig.Terraform.TerraformProvider.Resources)
+	assert.Equal(t, config.Network, readConfig.Network)
+
+	// an existing config.yml is kept when the user does not agree to overwrite it
+	assert.Nil(t, os.WriteFile(configPath, []byte("edited"), 0644))
+	assert.Nil(t, wizard.writeConfigYaml(config))
+	content, err = os.ReadFile(configPath)
+	assert.Nil(t, err)
+	assert.Equal(t, "edited", string(content))
+}
+
+func TestProjectWizard_Run_Network(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// The registry is searched with the retries of the options, not the ones of the default client
+	wizard := NewProjectWizard(&NewProjectOptions{
+		SearchQuery: "foo",
+		RegistryUrl: server.URL,
+		Network:     Network{Retries: 1, RetryWait: "10ms"},
+		Logger:      NewNopLogger(),
+	}, NewPrompter(strings.NewReader(""), &bytes.Buffer{}, true))
+	_, err := wizard.Run(context.Background())
+	assert.ErrorIs(t, err, ErrNetwork)
+	assert.Equal(t, 2, requests)
+
+	wizard = NewProjectWizard(&NewProjectOptions{
+		SearchQuery: "foo",
+		RegistryUrl: server.URL,
+		Network:     Network{Timeout: "soon"},
+		Logger:      NewNopLogger(),
+	}, NewPrompter(strings.NewReader(""), &bytes.Buffer{}, true))
+	_, err = wizard.Run(context.Background())
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+	assert.Equal(t, 2, requests)
+}
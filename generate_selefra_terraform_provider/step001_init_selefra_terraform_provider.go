@@ -5,8 +5,6 @@ import (
 	"context"
 	"fmt"
 	"github.com/selefra/selefra-provider-sdk/terraform/provider"
-	"github.com/selefra/selefra-terraform-provider-scaffolding/provider_template/provider_template_v2_init"
-	"github.com/yezihack/colorlog"
 	"go/ast"
 	"go/parser"
 	"go/token"
@@ -15,12 +13,12 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
-	"text/template"
 )
 
 type SelefraTerraformProviderInit struct {
 	config          *Config
 	schemaIRManager *SchemaIRManager
+	summary         *GenerateSummary
 }
 
 func NewSelefraTerraformProviderInit(config *Config) *SelefraTerraformProviderInit {
@@ -30,6 +28,11 @@ func NewSelefraTerraformProviderInit(config *Config) *SelefraTerraformProviderIn
 	}
 }
 
+// GetSummary The resources resources.go was rewritten for, nil if it was not
+func (x *SelefraTerraformProviderInit) GetSummary() *GenerateSummary {
+	return x.summary
+}
+
 func (x *SelefraTerraformProviderInit) Run(ctx context.Context) error {
 
 	// generate schema.json
@@ -37,11 +40,36 @@ func (x *SelefraTerraformProviderInit) Run(ctx context.Context) error {
 		return err
 	}
 
+	return x.RewriteProject()
+}
+
+// RewriteProject Render the project from the schema.json that was already generated
+func (x *SelefraTerraformProviderInit) RewriteProject() error {
+
 	// rewrite provider.go
 	if err := x.RewirteProviderGo(); err != nil {
 		return err
 	}
 
+	// client.go is only written when the project does not have one yet
+	if err := x.RewriteClientGo(); err != nil {
+		return err
+	}
+
+	// provider_test.go, provider_config.go, terraform_bridge.go and resource_lister.go too
+	if err := x.RewriteProviderTestGo(); err != nil {
+		return err
+	}
+	if err := x.RewriteProviderConfigGo(); err != nil {
+		return err
+	}
+	if err := x.RewriteTerraformBridgeGo(); err != nil {
+		return err
+	}
+	if err := x.RewriteResourceListerGo(); err != nil {
+		return err
+	}
+
 	// rewrite resource.go
 	if err := x.RewriteResourcesGo(); err != nil {
 		return err
@@ -56,17 +84,17 @@ func (x *SelefraTerraformProviderInit) Run(ctx context.Context) error {
 
 func (x *SelefraTerraformProviderInit) RewriteGoMod() error {
 	goModPath := filepath.Join(x.config.Output.Directory, "go.mod")
-	file, err := os.ReadFile(goModPath)
+	file, err := x.config.GetFileSystem().ReadFile(goModPath)
 	if err != nil {
-		colorlog.Error("can not open file %s, error msg: %s", goModPath, err.Error())
+		x.config.GetLogger().Error("can not open file %s, error msg: %s", goModPath, err.Error())
 		return err
 	}
 	newGoModFile := strings.ReplaceAll(string(file), "module github.com/selefra/selefra-provider-template", "module "+x.config.Selefra.ModuleName)
-	err = os.WriteFile(goModPath, []byte(newGoModFile), os.ModePerm)
+	err = x.config.GetFileSystem().WriteFile(goModPath, []byte(newGoModFile), os.ModePerm)
 	if err != nil {
-		colorlog.Error("rewrite go.mod file error: %s", err.Error())
+		x.config.GetLogger().Error("rewrite go.mod file error: %s", err.Error())
 	} else {
-		colorlog.Info("rewrite go.mod file success")
+		x.config.GetLogger().Info("rewrite go.mod file success")
 	}
 	return err
 }
@@ -74,42 +102,129 @@ func (x *SelefraTerraformProviderInit) RewriteGoMod() error {
 func (x *SelefraTerraformProviderInit) RewirteProviderGo() error {
 	providerOutputDirectory := filepath.Join(x.config.Output.Directory, "provider")
 	pathOutputPath := filepath.Join(providerOutputDirectory, "provider.go")
-	if exists, err := PathExists(pathOutputPath); err == nil && exists {
-		colorlog.Info("file %s already exists, so do not regenerate", pathOutputPath)
+	if exists, err := x.config.GetFileSystem().Exists(pathOutputPath); err == nil && exists {
+		x.config.GetLogger().Info("file %s already exists, so do not regenerate", pathOutputPath)
 		return nil
 	}
 
-	t, err := template.New("provider.go").Parse(string(provider_template_v2_init.ProviderTemplate))
+	t, err := x.config.LoadTemplate(InitProviderTemplateName)
 	if err != nil {
 		return err
 	}
 	buffer := bytes.Buffer{}
-	renderParams := &InitProviderGoRenderParams{
-		SelefraProviderName:               x.config.Terraform.TerraformProvider.ParseProviderShortName(),
-		ModuleName:                        x.config.Selefra.ModuleName,
-		TerraformProviderExecuteFileSlice: x.config.Terraform.TerraformProvider.ExecuteFiles,
+	renderParams := x.newInitProviderGoRenderParams()
+	renderParams.TerraformProviderExecuteFileSlice = x.config.Terraform.TerraformProvider.GetPlatformExecuteFiles()
+	if problems := validateExecuteFiles(renderParams.TerraformProviderExecuteFileSlice); len(problems) != 0 {
+		return &ConfigValidationError{Problems: problems}
+	}
+	if !isPlatformCovered(renderParams.TerraformProviderExecuteFileSlice, HostPlatform()) {
+		x.config.GetLogger().Warn("%s has no file for %s, the generated provider will not run on this host", pathOutputPath, HostPlatform())
 	}
-	if err = t.ExecuteTemplate(&buffer, "provider.go", renderParams); err != nil {
+	if err = t.Execute(&buffer, renderParams); err != nil {
+		return fmt.Errorf("%w: render %s error: %s", ErrTemplate, InitProviderTemplateName, err.Error())
+	}
+	sourceBytes, err := formatGoSource(pathOutputPath, buffer.Bytes())
+	if err != nil {
 		return err
 	}
-	_ = os.MkdirAll(providerOutputDirectory, os.ModePerm)
-	if err := os.WriteFile(pathOutputPath, buffer.Bytes(), os.ModePerm); err != nil {
+	_ = x.config.GetFileSystem().MkdirAll(providerOutputDirectory, os.ModePerm)
+	if err := x.config.GetFileSystem().WriteFile(pathOutputPath, sourceBytes, os.ModePerm); err != nil {
 		return err
 	}
 	return nil
 }
 
+// RewriteProviderTestGo Write the test that the execute files of provider.go are complete, when the project does not
+// have provider_test.go yet
+func (x *SelefraTerraformProviderInit) RewriteProviderTestGo() error {
+	return x.writeFileOnce(InitProviderTestTemplateName, "provider_test.go", x.newInitProviderGoRenderParams())
+}
+
+// RewriteProviderConfigGo Write the default configuration and the validation of the provider configuration, from the
+// provider block of the terraform provider, when the project does not have provider_config.go yet
+func (x *SelefraTerraformProviderInit) RewriteProviderConfigGo() error {
+	terraformProviderSchemaIR, err := x.schemaIRManager.readTerraformSchemaIR()
+	if err != nil {
+		x.config.GetLogger().Error("read terraform schema IR failed: %s", err.Error())
+		return err
+	}
+	// Applied here rather than to schema.json, so that changing the list does not need the provider started again
+	terraformProvider := &x.config.Terraform.TerraformProvider
+	for _, name := range applyProviderConfigDefaults(terraformProviderSchemaIR.ProviderConfig, terraformProvider.ConfigDefaults) {
+		x.config.GetLogger().Warn("%s of terraform.provider.config-defaults is not an argument of the provider block, so ignored", name)
+	}
+	renderParams := x.newInitProviderGoRenderParams()
+	renderParams.ProviderConfigArguments = terraformProviderSchemaIR.ProviderConfig
+	renderParams.ProviderConfigTemplate = BuildProviderConfigTemplate(terraformProviderSchemaIR.ProviderConfig)
+	if renderParams.MultiClient {
+		renderParams.ProviderConfigTemplate += BuildClientsConfigTemplate()
+	}
+	return x.writeFileOnce(InitProviderConfigTemplateName, "provider_config.go", renderParams)
+}
+
+// RewriteTerraformBridgeGo Write the lifecycle of the terraform providers the clients start, when the project does not
+// have terraform_bridge.go yet
+func (x *SelefraTerraformProviderInit) RewriteTerraformBridgeGo() error {
+	return x.writeFileOnce(InitTerraformBridgeTemplateName, "terraform_bridge.go", x.newInitProviderGoRenderParams())
+}
+
+// RewriteResourceListerGo Write the function the generated ListResourceParamsFunc list the resources with through a
+// data source, when the project does not have resource_lister.go yet
+func (x *SelefraTerraformProviderInit) RewriteResourceListerGo() error {
+	return x.writeFileOnce(InitResourceListerTemplateName, "resource_lister.go", x.newInitProviderGoRenderParams())
+}
+
+func (x *SelefraTerraformProviderInit) RewriteClientGo() error {
+	return x.writeFileOnce(InitClientTemplateName, "client.go", x.newInitProviderGoRenderParams())
+}
+
+func (x *SelefraTerraformProviderInit) newInitProviderGoRenderParams() *InitProviderGoRenderParams {
+	return &InitProviderGoRenderParams{
+		SelefraProviderName: x.config.Terraform.TerraformProvider.ParseProviderShortName(),
+		ModuleName:          x.config.Selefra.ModuleName,
+		MultiClient:         x.config.Output.IsMultiClient(),
+	}
+}
+
+// Render the template into the file of the provider directory, a file that exists already belongs to the project and
+// is left as it is
+func (x *SelefraTerraformProviderInit) writeFileOnce(templateName, fileName string, renderParams *InitProviderGoRenderParams) error {
+	outputPath := filepath.Join(x.config.Output.Directory, "provider", fileName)
+	if exists, err := x.config.GetFileSystem().Exists(outputPath); err == nil && exists {
+		return nil
+	}
+
+	t, err := x.config.LoadTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	buffer := bytes.Buffer{}
+	if err = t.Execute(&buffer, renderParams); err != nil {
+		return fmt.Errorf("%w: render %s error: %s", ErrTemplate, templateName, err.Error())
+	}
+	sourceBytes, err := formatGoSource(outputPath, buffer.Bytes())
+	if err != nil {
+		return err
+	}
+	_ = x.config.GetFileSystem().MkdirAll(filepath.Dir(outputPath), os.ModePerm)
+	if err := x.config.GetFileSystem().WriteFile(outputPath, sourceBytes, os.ModePerm); err != nil {
+		return err
+	}
+	x.config.GetLogger().Info("write file %s success", outputPath)
+	return nil
+}
+
 func (x *SelefraTerraformProviderInit) RewriteResourcesGo() error {
 	// Load the existing resource
 	resourcesOutputDirectory := filepath.Join(x.config.Output.Directory, "provider")
 	resourcesOutputPath := filepath.Join(resourcesOutputDirectory, "resources.go")
 
 	existsResourceSet := x.ParseExistsResourceSet()
-	colorlog.Info("load exists resource %d", len(existsResourceSet))
+	x.config.GetLogger().Info("load exists resource %d", len(existsResourceSet))
 
 	terraformProviderSchemaIR, err := x.schemaIRManager.readTerraformSchemaIR()
 	if err != nil {
-		colorlog.Error("read terraform schema IR failed: %s", err.Error())
+		x.config.GetLogger().Error("read terraform schema IR failed: %s", err.Error())
 		return err
 	}
 
@@ -124,6 +239,20 @@ func (x *SelefraTerraformProviderInit) RewriteResourcesGo() error {
 		return terraformProviderSchemaIR.Resources[i].ResourceName < terraformProviderSchemaIR.Resources[j].ResourceName
 	})
 
+	// Every client lists the resources of its own account and region
+	listResourceParamsTodoBody := `			// TODO
+			return nil, nil`
+	if x.config.Output.IsMultiClient() {
+		listResourceParamsTodoBody = `			// The client of one entry of clients, list the resources of client.AccountID in client.Region
+			client := taskClient.(*Client)
+			_ = client
+			// TODO
+			return nil, nil`
+	}
+	// The resources a data source lists the ids of are listed with it
+	resourceListers := PairResourceListers(terraformProviderSchemaIR)
+	listedByDataSourceCount := 0
+
 	for _, terraformResourceSchemaIR := range terraformProviderSchemaIR.Resources {
 		if !x.config.IsResourceNeedGenerate(terraformResourceSchemaIR.ResourceName) {
 			continue
@@ -140,23 +269,27 @@ func GetResource_%s() *selefra_terraform_schema.SelefraTerraformResource {
 	return &selefra_terraform_schema.SelefraTerraformResource{
 		SelefraTableName:      "%s",
 		TerraformResourceName: "%s",
-		Description:           "%s",
+		Description:           %q,
 		SubTables:             nil,
 		ListResourceParamsFunc: func(ctx context.Context, clientMeta *schema.ClientMeta, taskClient any, task *schema.DataSourcePullTask, resultChannel chan<- any) ([]*selefra_terraform_schema.ResourceRequestParam, *schema.Diagnostics) {
-			// TODO
-			return nil, nil
+%s
 		},
 	}
 }
 
 `
-		resourceCodeString := fmt.Sprintf(s, terraformResourceSchemaIR.ResourceName, terraformResourceSchemaIR.ResourceName, terraformResourceSchemaIR.ResourceName, terraformResourceSchemaIR.ResourceName, terraformResourceSchemaIR.Description)
+		listResourceParamsBody := listResourceParamsTodoBody
+		if lister, exists := resourceListers[terraformResourceSchemaIR.ResourceName]; exists {
+			listResourceParamsBody = lister.buildListResourceParamsBody()
+			listedByDataSourceCount++
+		}
+		resourceCodeString := fmt.Sprintf(s, terraformResourceSchemaIR.ResourceName, terraformResourceSchemaIR.ResourceName, terraformResourceSchemaIR.ResourceName, terraformResourceSchemaIR.ResourceName, terraformResourceSchemaIR.Description, listResourceParamsBody)
 		resourceCodeBuff.WriteString(resourceCodeString)
 		newAddExistsCount++
 	}
 
 	prefix := ""
-	if exists, err := PathExists(resourcesOutputPath); !exists || err != nil {
+	if exists, err := x.config.GetFileSystem().Exists(resourcesOutputPath); !exists || err != nil {
 		prefix = `package provider
 
 import (
@@ -170,25 +303,40 @@ import (
 	}
 
 	// append code to resource.go
-	file, err := os.OpenFile(resourcesOutputPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	existsResourceCode := ""
+	if prefix == "" {
+		fileBytes, err := x.config.GetFileSystem().ReadFile(resourcesOutputPath)
+		if err != nil {
+			x.config.GetLogger().Error("open %s error: %s", resourcesOutputPath, err.Error())
+			return err
+		}
+		existsResourceCode = string(fileBytes)
+	}
+	sourceBytes, err := formatGoSource(resourcesOutputPath, []byte(existsResourceCode+prefix+resourceCodeBuff.String()))
 	if err != nil {
-		colorlog.Error("open %s error: %s", resourcesOutputPath, err.Error())
+		x.config.GetLogger().Error("format %s error: %s", resourcesOutputPath, err.Error())
 		return err
 	}
-	_, err = file.WriteString(prefix + resourceCodeBuff.String())
+	err = x.config.GetFileSystem().WriteFile(resourcesOutputPath, sourceBytes, 0666)
 	if err != nil {
-		colorlog.Error("write %s error: %s", resourcesOutputPath, err.Error())
+		x.config.GetLogger().Error("write %s error: %s", resourcesOutputPath, err.Error())
 		return err
 	}
 
+	x.summary = &GenerateSummary{
+		Resources: resourceNeedGenerateCount,
+		Skipped:   terraformProviderSchemaIR.SkippedResources,
+	}
+
 	if len(ignoredResourceNameSlice) != 0 {
-		colorlog.Info("ignored resource: %s", ignoredResourceNameSlice)
+		x.config.GetLogger().Info("ignored resource: %s", ignoredResourceNameSlice)
 	}
 
-	colorlog.Info("init resource.go success: ")
-	colorlog.Info("\t\tTotal Need Generate Resource Count: %d", resourceNeedGenerateCount)
-	colorlog.Info("\t\tAlready Exists Resource Count: %d", alreadyExistsCount)
-	colorlog.Info("\t\tNew Add Resource Count: %d", newAddExistsCount)
+	x.config.GetLogger().Info("init resource.go success: ")
+	x.config.GetLogger().Info("\t\tTotal Need Generate Resource Count: %d", resourceNeedGenerateCount)
+	x.config.GetLogger().Info("\t\tAlready Exists Resource Count: %d", alreadyExistsCount)
+	x.config.GetLogger().Info("\t\tNew Add Resource Count: %d", newAddExistsCount)
+	x.config.GetLogger().Info("\t\tListed By Data Source Count: %d", listedByDataSourceCount)
 	return nil
 }
 
@@ -196,13 +344,18 @@ func (x *SelefraTerraformProviderInit) ParseExistsResourceSet() map[string]struc
 	existsResourceSet := make(map[string]struct{})
 	resourceGoOutputDirectory := filepath.Join(x.config.Output.Directory, "provider")
 	resourceGoOutputPath := filepath.Join(resourceGoOutputDirectory, "resources.go")
-	if exists, err := PathExists(resourceGoOutputPath); err != nil || !exists {
+	if exists, err := x.config.GetFileSystem().Exists(resourceGoOutputPath); err != nil || !exists {
+		return existsResourceSet
+	}
+	fileBytes, err := x.config.GetFileSystem().ReadFile(resourceGoOutputPath)
+	if err != nil {
+		x.config.GetLogger().Error("read resource.go file error: %s", err.Error())
 		return existsResourceSet
 	}
 	fileSet := token.NewFileSet()
-	f, err := parser.ParseFile(fileSet, resourceGoOutputPath, nil, parser.ParseComments)
+	f, err := parser.ParseFile(fileSet, resourceGoOutputPath, fileBytes, parser.ParseComments)
 	if err != nil {
-		colorlog.Error("parse resource.go file error: %s", err.Error())
+		x.config.GetLogger().Error("parse resource.go file error: %s", err.Error())
 		return existsResourceSet
 	}
 	astutil.Apply(f, func(cursor *astutil.Cursor) bool {
@@ -235,6 +388,13 @@ type InitProviderGoRenderParams struct {
 	SelefraProviderName               string
 	ModuleName                        string
 	TerraformProviderExecuteFileSlice []*provider.TerraformProviderFile
+
+	// The arguments of the provider block and the commented configuration built from them, see provider_config.go.tpl
+	ProviderConfigArguments []*TerraformProviderConfigArgumentIR
+	ProviderConfigTemplate  string
+
+	// Whether the provider starts one client for every entry of clients in its configuration, see Output.ClientMode
+	MultiClient bool
 }
 
 type InitProviderTablesGoRenderParams struct {
@@ -2,7 +2,10 @@ package generate_selefra_terraform_provider
 
 import (
 	"context"
+	"github.com/selefra/selefra-provider-sdk/terraform/provider"
 	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -26,3 +29,150 @@ func TestSelefraTerraformProviderInit_Run(t *testing.T) {
 	err = NewSelefraTerraformProviderInit(config).Run(context.Background())
 	assert.Nil(t, err)
 }
+
+func TestSelefraTerraformProviderInit_RewirteProviderGo(t *testing.T) {
+	file := &provider.TerraformProviderFile{
+		ProviderName:    "terraform-provider-test",
+		ProviderVersion: "1.0.0",
+		DownloadUrl:     "https://releases.example.com/terraform-provider-test_1.0.0_linux_amd64.zip",
+		OS:              "linux",
+		Arch:            "amd64",
+	}
+	config := &Config{
+		Selefra: Selefra{ModuleName: "github.com/selefra/selefra-provider-test"},
+		Terraform: Terraform{
+			TerraformProvider: TerraformProvider{
+				RepoUrl:      "https://github.com/selefra/terraform-provider-test",
+				ExecuteFiles: []*provider.TerraformProviderFile{file},
+			},
+		},
+		Output: Output{
+			Directory: t.TempDir(),
+		},
+	}
+	config.SetFileSystem(NewMemoryFileSystem(NewOsFileSystem()))
+	providerInit := NewSelefraTerraformProviderInit(config)
+
+	// No checksum
+	err := providerInit.RewirteProviderGo()
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+	assert.Contains(t, err.Error(), "terraform.provider.execute-files[0].sha256-sum")
+
+	file.Sha256Sum = strings.Repeat("a", 64)
+	assert.Nil(t, providerInit.RewirteProviderGo())
+	assert.Nil(t, providerInit.RewriteProviderTestGo())
+	providerGo, err := config.GetFileSystem().ReadFile(filepath.Join(config.Output.Directory, "provider", "provider.go"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(providerGo), `Sha256Sum:       "`+file.Sha256Sum+`"`)
+	providerTestGo, err := config.GetFileSystem().ReadFile(filepath.Join(config.Output.Directory, "provider", "provider_test.go"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(providerTestGo), "func Test_getTerraformProviderExecuteFileSlice(t *testing.T)")
+}
+
+func TestSelefraTerraformProviderInit_RewriteProviderConfigGo(t *testing.T) {
+	config := &Config{
+		Selefra: Selefra{ModuleName: "github.com/selefra/selefra-provider-test"},
+		Output: Output{
+			Directory: t.TempDir(),
+		},
+	}
+	config.SetLogger(NewNopLogger())
+	config.SetFileSystem(NewMemoryFileSystem(NewOsFileSystem()))
+	providerInit := NewSelefraTerraformProviderInit(config)
+
+	// No schema.json yet
+	assert.NotNil(t, providerInit.RewriteProviderConfigGo())
+
+	// The region may come from the environment of the terraform provider
+	config.Terraform.TerraformProvider.ConfigDefaults = []string{"region", "zone"}
+
+	ir := &TerraformProviderSchemaIR{ProviderName: "terraform-provider-test", ProviderConfig: FromTerraformProviderConfigSchema(newTestProviderConfigSchema())}
+	assert.Nil(t, providerInit.schemaIRManager.saveTerraformSchemaIR(ir))
+	assert.Nil(t, providerInit.RewriteProviderConfigGo())
+	providerConfigGo, err := config.GetFileSystem().ReadFile(filepath.Join(config.Output.Directory, "provider", "provider_config.go"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(providerConfigGo), `Name:       "region",`)
+	assert.Contains(t, string(providerConfigGo), "HasDefault: true,")
+	assert.Contains(t, string(providerConfigGo), "##  Required, defaults from the environment. The region of the resources.\n#region: \"\"\n")
+}
+
+func TestSelefraTerraformProviderInit_MultiClient(t *testing.T) {
+	config := &Config{
+		Selefra: Selefra{ModuleName: "github.com/selefra/selefra-provider-test"},
+		Terraform: Terraform{
+			TerraformProvider: TerraformProvider{
+				RepoUrl: "https://github.com/selefra/terraform-provider-test",
+				ExecuteFiles: []*provider.TerraformProviderFile{
+					{
+						ProviderName:    "terraform-provider-test",
+						ProviderVersion: "1.0.0",
+						DownloadUrl:     "https://releases.example.com/terraform-provider-test_1.0.0_linux_amd64.zip",
+						Sha256Sum:       strings.Repeat("a", 64),
+						OS:              "linux",
+						Arch:            "amd64",
+					},
+				},
+			},
+		},
+		Output: Output{
+			Directory:  t.TempDir(),
+			ClientMode: ClientModeMulti,
+		},
+	}
+	config.SetLogger(NewNopLogger())
+	config.SetFileSystem(NewMemoryFileSystem(NewOsFileSystem()))
+	providerInit := NewSelefraTerraformProviderInit(config)
+	ir := &TerraformProviderSchemaIR{
+		ProviderName:   "terraform-provider-test",
+		ProviderConfig: FromTerraformProviderConfigSchema(newTestProviderConfigSchema()),
+		Resources:      []*TerraformResourceSchemaIR{{ResourceName: "test_instance"}},
+	}
+	assert.Nil(t, providerInit.schemaIRManager.saveTerraformSchemaIR(ir))
+	assert.Nil(t, providerInit.RewirteProviderGo())
+	assert.Nil(t, providerInit.RewriteClientGo())
+	assert.Nil(t, providerInit.RewriteProviderConfigGo())
+	assert.Nil(t, providerInit.RewriteResourcesGo())
+
+	readFile := func(fileName string) string {
+		content, err := config.GetFileSystem().ReadFile(filepath.Join(config.Output.Directory, "provider", fileName))
+		assert.Nil(t, err)
+		return string(content)
+	}
+	assert.Contains(t, readFile("provider.go"), "clientConfigs, err := getClientConfigs(config)")
+	assert.Contains(t, readFile("provider.go"), "managedBridge, err := terraformBridges.Start(providerExecFilePath, clientConfig.Config)")
+	assert.Contains(t, readFile("client.go"), "AccountID string")
+	providerConfigGo := readFile("provider_config.go")
+	assert.Contains(t, providerConfigGo, "func getClientConfigs(config *viper.Viper) ([]*clientConfig, error)")
+	assert.Contains(t, providerConfigGo, "#clients:\n#  -\n#    ##  Optional. The account the client pulls the resources of.\n#    account_id: \"\"\n")
+	assert.Contains(t, readFile("resources.go"), "client := taskClient.(*Client)")
+}
+
+func TestSelefraTerraformProviderInit_RewriteTerraformBridgeGo(t *testing.T) {
+	config := &Config{
+		Selefra: Selefra{ModuleName: "github.com/selefra/selefra-provider-test"},
+		Output: Output{
+			Directory: t.TempDir(),
+		},
+	}
+	config.SetLogger(NewNopLogger())
+	config.SetFileSystem(NewMemoryFileSystem(NewOsFileSystem()))
+	mainGoPath := filepath.Join(config.Output.Directory, "main.go")
+
+	// A project without terraform_bridge.go does not shut down the terraform providers
+	assert.Nil(t, NewMainGenerator(config).Run())
+	mainGo, err := config.GetFileSystem().ReadFile(mainGoPath)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(mainGo), "ShutdownTerraformBridges")
+
+	assert.Nil(t, NewSelefraTerraformProviderInit(config).RewriteTerraformBridgeGo())
+	terraformBridgeGo, err := config.GetFileSystem().ReadFile(filepath.Join(config.Output.Directory, "provider", "terraform_bridge.go"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(terraformBridgeGo), "func SetTerraformBridgeContext(ctx context.Context)")
+
+	assert.Nil(t, NewMainGenerator(config).Run())
+	mainGo, err = config.GetFileSystem().ReadFile(mainGoPath)
+	assert.Nil(t, err)
+	assert.Contains(t, string(mainGo), "resources.SetTerraformBridgeContext(ctx)")
+	assert.Contains(t, string(mainGo), "signal.Notify(signals, os.Interrupt, syscall.SIGTERM)")
+	assert.Contains(t, string(mainGo), "os.Exit(0)")
+}
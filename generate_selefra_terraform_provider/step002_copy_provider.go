@@ -2,9 +2,9 @@ package generate_selefra_terraform_provider
 
 import (
 	"bytes"
-	"github.com/yezihack/colorlog"
+	"fmt"
+	"go/format"
 	"go/parser"
-	"go/printer"
 	"go/token"
 	"os"
 	"path/filepath"
@@ -27,40 +27,59 @@ func (x *CopyProvider) Run() error {
 	destinationDirectory := filepath.Join(x.config.Output.Directory, "resources")
 
 	// delete
-	err := os.RemoveAll(destinationDirectory)
+	err := x.removeDestination(destinationDirectory)
 	if err != nil {
-		colorlog.Error("remove directory %s failed: %s", destinationDirectory, err.Error())
+		x.config.GetLogger().Error("remove directory %s failed: %s", destinationDirectory, err.Error())
 	} else {
-		colorlog.Info("remove directory %s successfully", destinationDirectory)
+		x.config.GetLogger().Info("remove directory %s successfully", destinationDirectory)
 	}
 
-	err = filepath.Walk(sourceDirectory, func(sourcePath string, info os.FileInfo, err error) error {
+	sourcePathSlice, err := x.config.GetFileSystem().ListFiles(sourceDirectory)
+	if err != nil {
+		x.config.GetLogger().Error("list directory %s failed: %s", sourceDirectory, err.Error())
+		return err
+	}
+	for _, sourcePath := range sourcePathSlice {
 		destinationPath := x.computeDestinationPath(sourceDirectory, destinationDirectory, sourcePath)
-		if info.IsDir() {
-			err := os.MkdirAll(destinationPath, os.ModeDir|os.ModePerm)
-			if err != nil {
-				colorlog.Error("create directory %s failed: %s", destinationPath, err.Error())
-			} else {
-				colorlog.Info("create directory %s success", destinationPath)
-			}
-			return nil
+		err := x.config.GetFileSystem().MkdirAll(filepath.Dir(destinationPath), os.ModeDir|os.ModePerm)
+		if err != nil {
+			x.config.GetLogger().Error("create directory %s failed: %s", filepath.Dir(destinationPath), err.Error())
+			return err
 		}
 		fileBytes, err := x.processGoFile(sourcePath)
 		if err != nil {
-			colorlog.Error("process file %s failed: %s", sourcePath, err.Error())
+			x.config.GetLogger().Error("process file %s failed: %s", sourcePath, err.Error())
 			return err
 		}
-		err = os.WriteFile(destinationPath, fileBytes, os.ModePerm)
+		err = x.config.GetFileSystem().WriteFile(destinationPath, fileBytes, os.ModePerm)
 		if err != nil {
-			colorlog.Error("copy file %s failed: %s", sourcePath, err.Error())
+			x.config.GetLogger().Error("copy file %s failed: %s", sourcePath, err.Error())
 			return err
-		} else {
-			colorlog.Info("copy file %s to %s success", sourcePath, destinationPath)
-			return nil
 		}
-	})
+		x.config.GetLogger().Info("copy file %s to %s success", sourcePath, destinationPath)
+	}
 
-	return err
+	return nil
+}
+
+// The generated table files of the per table layout are kept, so that the schema generator can tell which of them changed
+func (x *CopyProvider) removeDestination(destinationDirectory string) error {
+	if x.config.Output.GetSchemaLayoutOrDefault() != SchemaLayoutPerTable {
+		return x.config.GetFileSystem().RemoveAll(destinationDirectory)
+	}
+	existsFileSlice, err := x.config.GetFileSystem().ListFiles(destinationDirectory)
+	if err != nil {
+		return err
+	}
+	for _, existsFile := range existsFileSlice {
+		if isGeneratedTableGoFile(x.config.GetFileSystem(), destinationDirectory, existsFile) {
+			continue
+		}
+		if err := x.config.GetFileSystem().RemoveAll(existsFile); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (x *CopyProvider) computeDestinationPath(sourceDirectory, destinationDirectory, sourcePath string) string {
@@ -69,7 +88,7 @@ func (x *CopyProvider) computeDestinationPath(sourceDirectory, destinationDirect
 	sourceDirectory = strings.ReplaceAll(sourceDirectory, "\\", "/")
 	index := strings.Index(sourcePath, sourceDirectory)
 	if index == -1 {
-		colorlog.Error("destination directory error, sourceDirectory = %s, destinationDirectory = %s, sourcePath = %s", sourceDirectory, destinationDirectory, sourcePath)
+		x.config.GetLogger().Error("destination directory error, sourceDirectory = %s, destinationDirectory = %s, sourcePath = %s", sourceDirectory, destinationDirectory, sourcePath)
 		return ""
 	}
 	if index+len(sourceDirectory)+1 > len(sourcePath) {
@@ -79,14 +98,15 @@ func (x *CopyProvider) computeDestinationPath(sourceDirectory, destinationDirect
 }
 
 func (x *CopyProvider) processGoFile(filepath string) ([]byte, error) {
-	if !strings.HasSuffix(filepath, ".go") {
-		return os.ReadFile(filepath)
+	fileBytes, err := x.config.GetFileSystem().ReadFile(filepath)
+	if err != nil || !strings.HasSuffix(filepath, ".go") {
+		return fileBytes, err
 	}
-	colorlog.Info("begin ast parse go file %s...", filepath)
+	x.config.GetLogger().Info("begin ast parse go file %s...", filepath)
 	fileSet := token.NewFileSet()
-	f, err := parser.ParseFile(fileSet, filepath, nil, parser.ParseComments)
+	f, err := parser.ParseFile(fileSet, filepath, fileBytes, parser.ParseComments)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %s", ErrParse, err.Error())
 	}
 	f.Name.Name = "resources"
 	//astutil.Apply(f, func(cursor *astutil.Cursor) bool {
@@ -99,7 +119,7 @@ func (x *CopyProvider) processGoFile(filepath string) ([]byte, error) {
 	//}, nil)
 
 	buff := bytes.Buffer{}
-	err = printer.Fprint(&buff, fileSet, f)
+	err = format.Node(&buff, fileSet, f)
 	if err != nil {
 		return nil, err
 	}
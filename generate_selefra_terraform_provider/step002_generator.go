@@ -1,13 +1,30 @@
 package generate_selefra_terraform_provider
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"github.com/selefra/selefra-provider-sdk/terraform/bridge"
+	"path/filepath"
 )
 
 type Generator struct {
-	config *Config
-	bridge *bridge.TerraformBridge
+	config  *Config
+	bridge  *bridge.TerraformBridge
+	summary *GenerateSummary
+}
+
+// GenerateSummary What a run of the generator produced
+type GenerateSummary struct {
+
+	// The terraform resources in the schema IR
+	Resources int
+
+	// The tables that were rendered for them
+	Tables int
+
+	// The resources no table is rendered for and why
+	Skipped []*SkippedResource
 }
 
 func NewGenerator(config *Config) *Generator {
@@ -16,8 +33,64 @@ func NewGenerator(config *Config) *Generator {
 	}
 }
 
+// GetSummary The summary of the last run, nil if it did not get as far as reading the schema IR
+func (x *Generator) GetSummary() *GenerateSummary {
+	return x.summary
+}
+
+func summarize(terraformSchemaIR *TerraformProviderSchemaIR, selefraProviderRenderParams *SelefraProviderRenderParams) *GenerateSummary {
+	summary := &GenerateSummary{
+		Resources: len(terraformSchemaIR.Resources),
+		Tables:    len(selefraProviderRenderParams.TableSlice),
+	}
+	tableSet := make(map[string]struct{}, len(selefraProviderRenderParams.TableSlice))
+	for _, table := range selefraProviderRenderParams.TableSlice {
+		tableSet[table.TableName] = struct{}{}
+	}
+	summary.Skipped = append(summary.Skipped, terraformSchemaIR.SkippedResources...)
+	for _, resource := range terraformSchemaIR.Resources {
+		if _, exists := tableSet[resource.ResourceName]; !exists {
+			summary.Skipped = append(summary.Skipped, &SkippedResource{ResourceName: resource.ResourceName, Reason: skippedReasonNoIdColumn})
+		}
+	}
+	return summary
+}
+
+// The tables of the multi client mode read the account and the region of the client, a project initialized in the
+// single client mode does not have them, its provider files are only written by init when they do not exist yet
+func (x *Generator) checkMultiClientProject() error {
+	providerDirectory := filepath.Join(x.config.Output.Directory, "provider")
+	problems := make([]*ConfigProblem, 0)
+	for _, file := range []struct{ name, declaration string }{
+		{name: "client.go", declaration: "AccountID"},
+		{name: "provider_config.go", declaration: "func getClientConfigs("},
+		// Otherwise only the one client of the arguments is started and the clients list is ignored
+		{name: "provider.go", declaration: "getClientConfigs(config)"},
+	} {
+		path := filepath.Join(providerDirectory, file.name)
+		content, err := x.config.GetFileSystem().ReadFile(path)
+		if err == nil && bytes.Contains(content, []byte(file.declaration)) {
+			continue
+		}
+		problems = append(problems, &ConfigProblem{
+			Key:     "output.client-mode",
+			Message: fmt.Sprintf("is multi but %s was not initialized in that mode, move it aside and run init again", path),
+		})
+	}
+	if len(problems) != 0 {
+		return &ConfigValidationError{Problems: problems}
+	}
+	return nil
+}
+
 func (x *Generator) Run() error {
 
+	if x.config.Output.IsMultiClient() {
+		if err := x.checkMultiClientProject(); err != nil {
+			return err
+		}
+	}
+
 	terraformSchemaIR, err := NewSchemaIRManager(x.config).ReadOrGenerateSchemaIR(context.Background())
 	if err != nil {
 		return err
@@ -28,6 +101,13 @@ func (x *Generator) Run() error {
 	}
 
 	selefraProviderRenderParams := terraformSchemaIR.ToSelefraProviderRenderParams(x.config.Selefra.ModuleName)
+	if x.config.Output.IsMultiClient() {
+		selefraProviderRenderParams.AddClientColumns()
+	}
+	x.summary = summarize(terraformSchemaIR, selefraProviderRenderParams)
+	for _, skipped := range x.summary.Skipped {
+		x.config.GetLogger().Warn("no table is generated for terraform resource %s, %s", skipped.ResourceName, skipped.Reason)
+	}
 	if err := NewSchemaGeneratorV2(x.config, selefraProviderRenderParams).Run(context.Background()); err != nil {
 		return err
 	}
@@ -1,7 +1,9 @@
 package generate_selefra_terraform_provider
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"path/filepath"
 	"testing"
 )
 
@@ -25,3 +27,49 @@ func TestGenerator_Run(t *testing.T) {
 	assert.Nil(t, err)
 
 }
+
+func TestGenerator_Run_MultiClientOnSingleClientProject(t *testing.T) {
+	config := &Config{
+		Selefra: Selefra{ModuleName: "github.com/selefra/selefra-provider-test"},
+		Output: Output{
+			Directory: t.TempDir(),
+		},
+	}
+	config.SetLogger(NewNopLogger())
+	config.SetFileSystem(NewMemoryFileSystem(NewOsFileSystem()))
+	providerInit := NewSelefraTerraformProviderInit(config)
+	ir := &TerraformProviderSchemaIR{
+		ProviderName:   "terraform-provider-test",
+		ProviderConfig: FromTerraformProviderConfigSchema(newTestProviderConfigSchema()),
+		Resources:      []*TerraformResourceSchemaIR{{ResourceName: "test_instance"}},
+	}
+	assert.Nil(t, providerInit.schemaIRManager.saveTerraformSchemaIR(ir))
+	assert.Nil(t, providerInit.RewriteClientGo())
+	assert.Nil(t, providerInit.RewriteProviderConfigGo())
+	assert.Nil(t, providerInit.RewirteProviderGo())
+
+	config.Output.ClientMode = ClientModeMulti
+	err := NewGenerator(config).Run()
+	var validationError *ConfigValidationError
+	assert.True(t, errors.As(err, &validationError))
+	assert.ErrorIs(t, err, ErrCheckConfigFailed)
+	assert.Len(t, validationError.Problems, 3)
+	assert.Equal(t, "output.client-mode", validationError.Problems[0].Key)
+	assert.Contains(t, validationError.Problems[0].Message, "client.go was not initialized in that mode, move it aside and run init again")
+
+	// Only client.go and provider_config.go were moved aside, provider.go still starts one client
+	for _, fileName := range []string{"client.go", "provider_config.go"} {
+		assert.Nil(t, config.GetFileSystem().RemoveAll(filepath.Join(config.Output.Directory, "provider", fileName)))
+	}
+	assert.Nil(t, providerInit.RewriteClientGo())
+	assert.Nil(t, providerInit.RewriteProviderConfigGo())
+	err = NewGenerator(config).checkMultiClientProject()
+	assert.True(t, errors.As(err, &validationError))
+	assert.Len(t, validationError.Problems, 1)
+	assert.Contains(t, validationError.Problems[0].Message, filepath.Join("provider", "provider.go")+" was not initialized in that mode")
+
+	// Initialized again in the multi client mode
+	assert.Nil(t, config.GetFileSystem().RemoveAll(filepath.Join(config.Output.Directory, "provider", "provider.go")))
+	assert.Nil(t, providerInit.RewirteProviderGo())
+	assert.Nil(t, NewGenerator(config).checkMultiClientProject())
+}
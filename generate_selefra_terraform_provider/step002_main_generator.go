@@ -2,10 +2,9 @@ package generate_selefra_terraform_provider
 
 import (
 	"bytes"
-	"github.com/selefra/selefra-terraform-provider-scaffolding/provider_template/provider_template_v2_generate"
+	"fmt"
 	"os"
 	"path/filepath"
-	"text/template"
 )
 
 type MainGenerator struct {
@@ -20,7 +19,7 @@ func NewMainGenerator(config *Config) *MainGenerator {
 
 func (x *MainGenerator) Run() error {
 
-	t, err := template.New("main.go").Parse(string(provider_template_v2_generate.MainTemplate))
+	t, err := x.config.LoadTemplate(MainTemplateName)
 	if err != nil {
 		return err
 	}
@@ -28,13 +27,22 @@ func (x *MainGenerator) Run() error {
 	renderParams := MainRenderParams{
 		ModuleName: x.config.Selefra.ModuleName,
 	}
-	if err = t.ExecuteTemplate(&buffer, "main.go", renderParams); err != nil {
-		return err
+	// A project initialized before the terraform providers lived as long as the provider does not have it
+	terraformBridgeGoPath := filepath.Join(x.config.Output.Directory, "provider", "terraform_bridge.go")
+	if content, err := x.config.GetFileSystem().ReadFile(terraformBridgeGoPath); err == nil && bytes.Contains(content, []byte("func SetTerraformBridgeContext(")) {
+		renderParams.TerraformBridgeContext = true
+	}
+	if err = t.Execute(&buffer, renderParams); err != nil {
+		return fmt.Errorf("%w: render %s error: %s", ErrTemplate, MainTemplateName, err.Error())
 	}
 
-	_ = os.MkdirAll(x.config.Output.Directory, os.ModePerm)
+	_ = x.config.GetFileSystem().MkdirAll(x.config.Output.Directory, os.ModePerm)
 	mainFileOutputPath := filepath.Join(x.config.Output.Directory, "main.go")
-	if err := os.WriteFile(mainFileOutputPath, buffer.Bytes(), os.ModePerm); err != nil {
+	sourceBytes, err := formatGoSource(mainFileOutputPath, buffer.Bytes())
+	if err != nil {
+		return err
+	}
+	if err := x.config.GetFileSystem().WriteFile(mainFileOutputPath, sourceBytes, os.ModePerm); err != nil {
 		return err
 	}
 	return nil
@@ -42,4 +50,7 @@ func (x *MainGenerator) Run() error {
 
 type MainRenderParams struct {
 	ModuleName string
+
+	// Whether the provider has SetTerraformBridgeContext and ShutdownTerraformBridges, see terraform_bridge.go.tpl
+	TerraformBridgeContext bool
 }
@@ -3,13 +3,12 @@ package generate_selefra_terraform_provider
 import (
 	"bytes"
 	"context"
-	"github.com/selefra/selefra-terraform-provider-scaffolding/provider_template/provider_template_v2_generate"
-	"github.com/yezihack/colorlog"
+	"crypto/sha256"
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"golang.org/x/tools/go/ast/astutil"
-	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -32,7 +31,7 @@ func (x *SchemaGenerator) Run(ctx context.Context) error {
 
 	// Use the number of resources under resources.go to determine whether the corresponding schema is generated
 	resources, err := x.GetResources()
-	colorlog.Info("resources count: %d", len(resources))
+	x.config.GetLogger().Info("resources count: %d", len(resources))
 	if err != nil {
 		return err
 	}
@@ -44,55 +43,129 @@ func (x *SchemaGenerator) Run(ctx context.Context) error {
 	}
 	x.selefraProviderRenderParams.TableSlice = newTableSlice
 
-	t, err := template.New("schema.go").Parse(string(provider_template_v2_generate.SelefraSchemaTemplate))
+	t, err := x.config.LoadTemplate(SelefraSchemaTemplateName, SelefraTableSchemaTemplateName, SelefraTableTemplateName, SelefraTableRegistryTemplateName)
 	if err != nil {
-		colorlog.Error("parse schema.go template error: %s", err.Error())
+		x.config.GetLogger().Error("parse schema.go template error: %s", err.Error())
 		return err
 	}
 
-	buffer := bytes.Buffer{}
-	if err = t.ExecuteTemplate(&buffer, "schema.go", x.selefraProviderRenderParams); err != nil {
-		colorlog.Error("render schema.go error: %s", err.Error())
+	schemaGoOutputDirectory := filepath.Join(x.config.Output.Directory, "resources")
+	_ = x.config.GetFileSystem().MkdirAll(schemaGoOutputDirectory, os.ModePerm)
+
+	if x.config.Output.GetSchemaLayoutOrDefault() == SchemaLayoutPerTable {
+		return x.renderPerTable(t, schemaGoOutputDirectory)
+	}
+
+	return x.renderGoFile(t, SelefraSchemaTemplateName, x.selefraProviderRenderParams, filepath.Join(schemaGoOutputDirectory, "selefra_schema.go"))
+}
+
+// Each table goes into its own file, a table whose rendered content is the same as the file already on disk is skipped
+func (x *SchemaGenerator) renderPerTable(t *template.Template, schemaGoOutputDirectory string) error {
+
+	writeCount := 0
+	unchangedCount := 0
+	tableFileSet := make(map[string]struct{})
+	for _, table := range x.selefraProviderRenderParams.TableSlice {
+		tableGoOutputPath := filepath.Join(schemaGoOutputDirectory, buildTableGoFileName(table.TableName))
+		tableFileSet[filepath.Base(tableGoOutputPath)] = struct{}{}
+
+		buffer := bytes.Buffer{}
+		if err := t.ExecuteTemplate(&buffer, SelefraTableTemplateName, table); err != nil {
+			x.config.GetLogger().Error("render %s error: %s", tableGoOutputPath, err.Error())
+			return fmt.Errorf("%w: render %s error: %s", ErrTemplate, tableGoOutputPath, err.Error())
+		}
+		sourceBytes, err := formatGoSource(tableGoOutputPath, buffer.Bytes())
+		if err != nil {
+			x.config.GetLogger().Error("format file %s error: %s", tableGoOutputPath, err.Error())
+			return err
+		}
+		if existsBytes, err := x.config.GetFileSystem().ReadFile(tableGoOutputPath); err == nil && sha256.Sum256(existsBytes) == sha256.Sum256(sourceBytes) {
+			unchangedCount++
+			continue
+		}
+		if err := x.config.GetFileSystem().WriteFile(tableGoOutputPath, sourceBytes, os.ModePerm); err != nil {
+			x.config.GetLogger().Error("write file %s error: %s", tableGoOutputPath, err.Error())
+			return err
+		}
+		writeCount++
+	}
+
+	// The tables that no longer exist would otherwise still be compiled in
+	removeCount := 0
+	existsFileSlice, err := x.config.GetFileSystem().ListFiles(schemaGoOutputDirectory)
+	if err != nil {
 		return err
 	}
+	for _, existsFile := range existsFileSlice {
+		if _, exists := tableFileSet[filepath.Base(existsFile)]; exists || !isGeneratedTableGoFile(x.config.GetFileSystem(), schemaGoOutputDirectory, existsFile) {
+			continue
+		}
+		if err := x.config.GetFileSystem().RemoveAll(existsFile); err != nil {
+			x.config.GetLogger().Error("remove file %s error: %s", existsFile, err.Error())
+			return err
+		}
+		removeCount++
+	}
 
-	schemaGoOutputDirectory := filepath.Join(x.config.Output.Directory, "resources")
-	_ = os.MkdirAll(schemaGoOutputDirectory, os.ModePerm)
-	schemaGoOutputPath := filepath.Join(schemaGoOutputDirectory, "selefra_schema.go")
-	if err := os.WriteFile(schemaGoOutputPath, buffer.Bytes(), os.ModePerm); err != nil {
-		colorlog.Error("write file %s error: %s", schemaGoOutputPath, err.Error())
+	x.config.GetLogger().Info("render table files done: ")
+	x.config.GetLogger().Info("\t\tWrite Count: %d", writeCount)
+	x.config.GetLogger().Info("\t\tUnchanged Count: %d", unchangedCount)
+	x.config.GetLogger().Info("\t\tRemove Count: %d", removeCount)
+
+	return x.renderGoFile(t, SelefraTableRegistryTemplateName, x.selefraProviderRenderParams, filepath.Join(schemaGoOutputDirectory, "selefra_table_registry.go"))
+}
+
+func (x *SchemaGenerator) renderGoFile(t *template.Template, templateName string, renderParams any, outputPath string) error {
+	buffer := bytes.Buffer{}
+	if err := t.ExecuteTemplate(&buffer, templateName, renderParams); err != nil {
+		x.config.GetLogger().Error("render %s error: %s", templateName, err.Error())
+		return fmt.Errorf("%w: render %s error: %s", ErrTemplate, templateName, err.Error())
+	}
+	sourceBytes, err := formatGoSource(outputPath, buffer.Bytes())
+	if err != nil {
+		x.config.GetLogger().Error("format file %s error: %s", outputPath, err.Error())
 		return err
 	}
-	colorlog.Info("write file %s success", schemaGoOutputPath)
+	if err := x.config.GetFileSystem().WriteFile(outputPath, sourceBytes, os.ModePerm); err != nil {
+		x.config.GetLogger().Error("write file %s error: %s", outputPath, err.Error())
+		return err
+	}
+	x.config.GetLogger().Info("write file %s success", outputPath)
 	return nil
 }
 
 func (x *SchemaGenerator) GetResources() (map[string]struct{}, error) {
 	resourcesOutputDirectory := filepath.Join(x.config.Output.Directory, "provider")
-	fileSet := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fileSet, resourcesOutputDirectory, func(info fs.FileInfo) bool {
-		return true
-	}, parser.ParseComments)
+	sourcePathSlice, err := x.config.GetFileSystem().ListFiles(resourcesOutputDirectory)
 	if err != nil {
 		return nil, err
 	}
+	fileSet := token.NewFileSet()
 	resourceNameSet := make(map[string]struct{}, 0)
-	for _, pkg := range pkgs {
-		for _, f := range pkg.Files {
-			for _, decl := range f.Decls {
-				astutil.Apply(decl, func(cursor *astutil.Cursor) bool {
-					funcDecl, ok := decl.(*ast.FuncDecl)
-					if !ok {
-						return true
-					}
-					tableName := x.parseTableName(funcDecl)
-					if tableName != "" {
-						resourceNameSet[tableName] = struct{}{}
-					}
+	for _, sourcePath := range sourcePathSlice {
+		if filepath.Dir(sourcePath) != filepath.Clean(resourcesOutputDirectory) || !strings.HasSuffix(sourcePath, ".go") {
+			continue
+		}
+		fileBytes, err := x.config.GetFileSystem().ReadFile(sourcePath)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fileSet, sourcePath, fileBytes, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrParse, err.Error())
+		}
+		for _, decl := range f.Decls {
+			astutil.Apply(decl, func(cursor *astutil.Cursor) bool {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok {
 					return true
-				}, nil)
-			}
-
+				}
+				tableName := x.parseTableName(funcDecl)
+				if tableName != "" {
+					resourceNameSet[tableName] = struct{}{}
+				}
+				return true
+			}, nil)
 		}
 	}
 	return resourceNameSet, nil
@@ -107,3 +180,30 @@ func (x *SchemaGenerator) parseTableName(funcDecl *ast.FuncDecl) string {
 	s := funcDecl.Body.List[0].(*ast.ReturnStmt).Results[0].(*ast.UnaryExpr).X.(*ast.CompositeLit).Elts[0].(*ast.KeyValueExpr).Value.(*ast.BasicLit).Value
 	return strings.Trim(s, "\"")
 }
+
+// The file name of the per table layout, table_<name>.go, unless the name happens to end with something the go tool
+// would read as a build constraint, such as _test, _windows or _arm64
+func buildTableGoFileName(tableName string) string {
+	osSet, archSet := getGoAllowArchAndOS()
+	lastWord := tableName[strings.LastIndex(tableName, "_")+1:]
+	_, isOS := osSet[lastWord]
+	_, isArch := archSet[lastWord]
+	if isOS || isArch || lastWord == "test" {
+		return "table_" + tableName + "_table.go"
+	}
+	return "table_" + tableName + ".go"
+}
+
+// The first line of every file rendered from the generate templates
+const generatedGoFileHeader = "// Code generated by https://github.com/selefra/selefra-terraform-provider-scaffolding DO NOT EDIT."
+
+// Whether the file is a table file of the per table layout, the table_*.go files written by hand into provider/ that
+// are copied next to them do not start with the generated header
+func isGeneratedTableGoFile(fileSystem FileSystem, schemaGoOutputDirectory, path string) bool {
+	name := filepath.Base(path)
+	if filepath.Dir(path) != filepath.Clean(schemaGoOutputDirectory) || !strings.HasPrefix(name, "table_") || !strings.HasSuffix(name, ".go") {
+		return false
+	}
+	fileBytes, err := fileSystem.ReadFile(path)
+	return err == nil && bytes.HasPrefix(fileBytes, []byte(generatedGoFileHeader))
+}
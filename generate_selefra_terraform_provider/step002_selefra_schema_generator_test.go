@@ -0,0 +1,82 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"github.com/selefra/selefra-provider-sdk/provider/schema"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSchemaGenerator_Run_PerTable(t *testing.T) {
+	directory := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(directory, "provider"), os.ModePerm))
+	resourcesGo := `package provider
+
+func GetResource_aws_vpc() *selefra_terraform_schema.SelefraTerraformResource {
+	return &selefra_terraform_schema.SelefraTerraformResource{
+		SelefraTableName: "aws_vpc",
+	}
+}
+
+func GetResource_aws_subnet() *selefra_terraform_schema.SelefraTerraformResource {
+	return &selefra_terraform_schema.SelefraTerraformResource{
+		SelefraTableName: "aws_subnet",
+	}
+}
+`
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "provider", "resources.go"), []byte(resourcesGo), os.ModePerm))
+	config := &Config{
+		Output: Output{
+			Directory:    directory,
+			SchemaLayout: SchemaLayoutPerTable,
+		},
+	}
+	schemaIR := &TerraformProviderSchemaIR{
+		ProviderName: "terraform-provider-aws",
+		Resources: []*TerraformResourceSchemaIR{
+			{ResourceName: "aws_vpc", Columns: []*TerraformColumnSchemaIR{{ColumnName: "id", ColumnType: schema.ColumnTypeString}}},
+			{ResourceName: "aws_subnet", Columns: []*TerraformColumnSchemaIR{{ColumnName: "id", ColumnType: schema.ColumnTypeString}}},
+		},
+	}
+
+	// case 001. every table gets its own file plus the registry
+	assert.Nil(t, NewSchemaGeneratorV2(config, schemaIR.ToSelefraProviderRenderParams("github.com/selefra/foo")).Run(context.Background()))
+	for _, name := range []string{"table_aws_vpc.go", "table_aws_subnet.go", "selefra_table_registry.go"} {
+		exists, err := PathExists(filepath.Join(directory, "resources", name))
+		assert.Nil(t, err)
+		assert.True(t, exists, name)
+	}
+
+	// case 002. unchanged tables are not rewritten and a table that is gone is removed
+	vpcGoPath := filepath.Join(directory, "resources", "table_aws_vpc.go")
+	modTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Nil(t, os.Chtimes(vpcGoPath, modTime, modTime))
+	schemaIR.Resources = schemaIR.Resources[:1]
+	assert.Nil(t, NewSchemaGeneratorV2(config, schemaIR.ToSelefraProviderRenderParams("github.com/selefra/foo")).Run(context.Background()))
+	stat, err := os.Stat(vpcGoPath)
+	assert.Nil(t, err)
+	assert.True(t, stat.ModTime().Equal(modTime))
+	exists, err := PathExists(filepath.Join(directory, "resources", "table_aws_subnet.go"))
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	// case 003. a table_*.go file written by hand is kept
+	handWrittenGoPath := filepath.Join(directory, "resources", "table_helpers.go")
+	assert.Nil(t, os.WriteFile(handWrittenGoPath, []byte("package resources\n"), os.ModePerm))
+	assert.Nil(t, NewSchemaGeneratorV2(config, schemaIR.ToSelefraProviderRenderParams("github.com/selefra/foo")).Run(context.Background()))
+	exists, err = PathExists(handWrittenGoPath)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+func Test_buildTableGoFileName(t *testing.T) {
+	assert.Equal(t, "table_aws_vpc.go", buildTableGoFileName("aws_vpc"))
+	assert.Equal(t, "table_aws_ab_test_table.go", buildTableGoFileName("aws_ab_test"))
+	assert.Equal(t, "table_azurerm_os_windows_table.go", buildTableGoFileName("azurerm_os_windows"))
+	assert.Equal(t, "table_foo_runtime_wasip1_table.go", buildTableGoFileName("foo_runtime_wasip1"))
+	assert.Equal(t, "table_ibm_zos_table.go", buildTableGoFileName("ibm_zos"))
+	assert.Equal(t, "table_foo_sparc64_table.go", buildTableGoFileName("foo_sparc64"))
+}
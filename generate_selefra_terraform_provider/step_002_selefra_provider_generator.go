@@ -2,11 +2,9 @@ package generate_selefra_terraform_provider
 
 import (
 	"bytes"
-	"github.com/selefra/selefra-terraform-provider-scaffolding/provider_template/provider_template_v2_generate"
-	"github.com/yezihack/colorlog"
+	"fmt"
 	"os"
 	"path/filepath"
-	"text/template"
 )
 
 type ProviderGenerator struct {
@@ -22,25 +20,30 @@ func NewProviderGenerator(config *Config, selefraProviderRenderParams *SelefraPr
 }
 
 func (x *ProviderGenerator) Run() error {
-	t, err := template.New("provider.go").Parse(string(provider_template_v2_generate.SelefraProviderTemplate))
+	t, err := x.config.LoadTemplate(SelefraProviderTemplateName)
 	if err != nil {
-		colorlog.Error("parse provider.go template error: %s", err.Error())
+		x.config.GetLogger().Error("parse provider.go template error: %s", err.Error())
 		return err
 	}
 
 	buffer := bytes.Buffer{}
-	if err = t.ExecuteTemplate(&buffer, "provider.go", x.selefraProviderRenderParams); err != nil {
-		colorlog.Error("render provider.go error: %s", err.Error())
-		return err
+	if err = t.Execute(&buffer, x.selefraProviderRenderParams); err != nil {
+		x.config.GetLogger().Error("render provider.go error: %s", err.Error())
+		return fmt.Errorf("%w: render %s error: %s", ErrTemplate, SelefraProviderTemplateName, err.Error())
 	}
 
 	providerGoOutputDirectory := filepath.Join(x.config.Output.Directory, "resources")
-	_ = os.MkdirAll(providerGoOutputDirectory, os.ModePerm)
+	_ = x.config.GetFileSystem().MkdirAll(providerGoOutputDirectory, os.ModePerm)
 	providerGoOutputPath := filepath.Join(providerGoOutputDirectory, "selefra_provider.go")
-	if err := os.WriteFile(providerGoOutputPath, buffer.Bytes(), os.ModePerm); err != nil {
-		colorlog.Error("write file %s error: %s", providerGoOutputPath, err.Error())
+	sourceBytes, err := formatGoSource(providerGoOutputPath, buffer.Bytes())
+	if err != nil {
+		x.config.GetLogger().Error("format file %s error: %s", providerGoOutputPath, err.Error())
+		return err
+	}
+	if err := x.config.GetFileSystem().WriteFile(providerGoOutputPath, sourceBytes, os.ModePerm); err != nil {
+		x.config.GetLogger().Error("write file %s error: %s", providerGoOutputPath, err.Error())
 		return err
 	}
-	colorlog.Info("write file %s success", providerGoOutputPath)
+	x.config.GetLogger().Info("write file %s success", providerGoOutputPath)
 	return nil
 }
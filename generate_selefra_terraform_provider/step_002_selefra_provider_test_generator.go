@@ -2,11 +2,9 @@ package generate_selefra_terraform_provider
 
 import (
 	"bytes"
-	"github.com/selefra/selefra-terraform-provider-scaffolding/provider_template/provider_template_v2_generate"
-	"github.com/yezihack/colorlog"
+	"fmt"
 	"os"
 	"path/filepath"
-	"text/template"
 )
 
 type SelefraProviderTestGenerator struct {
@@ -22,25 +20,30 @@ func NewSelefraProviderTestGenerator(config *Config, selefraProviderRenderParams
 }
 
 func (x *SelefraProviderTestGenerator) Run() error {
-	t, err := template.New("selefra_provider_test.go").Parse(string(provider_template_v2_generate.SelefraProviderTestTemplate))
+	t, err := x.config.LoadTemplate(SelefraProviderTestTemplateName)
 	if err != nil {
-		colorlog.Error("parse selefra_provider_test.go template error: %s", err.Error())
+		x.config.GetLogger().Error("parse selefra_provider_test.go template error: %s", err.Error())
 		return err
 	}
 
 	buffer := bytes.Buffer{}
-	if err = t.ExecuteTemplate(&buffer, "selefra_provider_test.go", x.selefraProviderRenderParams); err != nil {
-		colorlog.Error("render selefra_provider_test.go error: %s", err.Error())
-		return err
+	if err = t.Execute(&buffer, x.selefraProviderRenderParams); err != nil {
+		x.config.GetLogger().Error("render selefra_provider_test.go error: %s", err.Error())
+		return fmt.Errorf("%w: render %s error: %s", ErrTemplate, SelefraProviderTestTemplateName, err.Error())
 	}
 
 	providerGoOutputDirectory := filepath.Join(x.config.Output.Directory, "resources")
-	_ = os.MkdirAll(providerGoOutputDirectory, os.ModePerm)
+	_ = x.config.GetFileSystem().MkdirAll(providerGoOutputDirectory, os.ModePerm)
 	providerGoOutputPath := filepath.Join(providerGoOutputDirectory, "selefra_provider_test.go")
-	if err := os.WriteFile(providerGoOutputPath, buffer.Bytes(), os.ModePerm); err != nil {
-		colorlog.Error("write file %s error: %s", providerGoOutputPath, err.Error())
+	sourceBytes, err := formatGoSource(providerGoOutputPath, buffer.Bytes())
+	if err != nil {
+		x.config.GetLogger().Error("format file %s error: %s", providerGoOutputPath, err.Error())
+		return err
+	}
+	if err := x.config.GetFileSystem().WriteFile(providerGoOutputPath, sourceBytes, os.ModePerm); err != nil {
+		x.config.GetLogger().Error("write file %s error: %s", providerGoOutputPath, err.Error())
 		return err
 	}
-	colorlog.Info("write file %s success", providerGoOutputPath)
+	x.config.GetLogger().Info("write file %s success", providerGoOutputPath)
 	return nil
 }
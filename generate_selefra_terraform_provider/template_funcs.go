@@ -0,0 +1,176 @@
+package generate_selefra_terraform_provider
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// templateFuncMap The functions available to every template, the embedded ones as well as the overrides in output.templates-dir:
+//
+//	camel       aws_vpc_endpoint -> awsVpcEndpoint
+//	pascal      aws_vpc_endpoint -> AwsVpcEndpoint
+//	snake       AwsVpcEndpoint   -> aws_vpc_endpoint
+//	goIdent     turns any string into a valid go identifier, 2fa-type -> _2fa_type, type -> type_
+//	quote       a double quoted go string literal, "a \"b\""
+//	backtick    a raw go string literal if the string allows it, a double quoted one otherwise
+//	indent      {{indent 2 .Code}} prefixes every non empty line with n tabs
+//	comment     prefixes every line with "// "
+//	sortedKeys  the keys of a map in dictionary order, {{range $key := sortedKeys .ImportSet}}
+//	join        {{join ", " .PrimaryKeys}} joins the elements of a slice with the separator
+func templateFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"camel":      toCamelCase,
+		"pascal":     toPascalCase,
+		"snake":      toSnakeCase,
+		"goIdent":    toGoIdent,
+		"quote":      strconv.Quote,
+		"backtick":   toBacktickLiteral,
+		"indent":     indentLines,
+		"comment":    commentLines,
+		"sortedKeys": sortedKeys,
+		"join":       joinSlice,
+	}
+}
+
+// newTemplate All templates are created here, so that they all get the same functions
+func newTemplate(name string) *template.Template {
+	return template.New(name).Funcs(templateFuncMap())
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// Split on everything that is not a letter or digit, and between a lower case letter and an upper case one
+func splitWords(s string) []string {
+	words := make([]string, 0)
+	word := strings.Builder{}
+	runes := []rune(s)
+	for index, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if word.Len() != 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			continue
+		}
+		// VpcID -> Vpc ID, HTTPServer -> HTTP Server
+		if unicode.IsUpper(r) && word.Len() != 0 && index > 0 {
+			previous := runes[index-1]
+			isNextLower := index+1 < len(runes) && unicode.IsLower(runes[index+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && isNextLower) {
+				words = append(words, word.String())
+				word.Reset()
+			}
+		}
+		word.WriteRune(r)
+	}
+	if word.Len() != 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+func toPascalCase(s string) string {
+	buff := strings.Builder{}
+	for _, word := range splitWords(s) {
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		buff.WriteString(string(runes))
+	}
+	return buff.String()
+}
+
+func toCamelCase(s string) string {
+	pascal := []rune(toPascalCase(s))
+	if len(pascal) == 0 {
+		return ""
+	}
+	pascal[0] = unicode.ToLower(pascal[0])
+	return string(pascal)
+}
+
+func toSnakeCase(s string) string {
+	words := splitWords(s)
+	for index, word := range words {
+		words[index] = strings.ToLower(word)
+	}
+	return strings.Join(words, "_")
+}
+
+func toGoIdent(s string) string {
+	buff := strings.Builder{}
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			buff.WriteRune(r)
+		} else {
+			buff.WriteRune('_')
+		}
+	}
+	ident := buff.String()
+	if ident == "" {
+		return "_"
+	}
+	if unicode.IsDigit([]rune(ident)[0]) {
+		ident = "_" + ident
+	}
+	if token.IsKeyword(ident) {
+		ident += "_"
+	}
+	return ident
+}
+
+func toBacktickLiteral(s string) string {
+	if strings.ContainsAny(s, "`\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+func indentLines(n int, s string) string {
+	prefix := strings.Repeat("\t", n)
+	lines := strings.Split(s, "\n")
+	for index, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[index] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func commentLines(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimRight("// "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func sortedKeys(m any) ([]string, error) {
+	value := reflect.ValueOf(m)
+	if value.Kind() != reflect.Map {
+		return nil, fmt.Errorf("sortedKeys expects a map, got %T", m)
+	}
+	keys := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		keys = append(keys, fmt.Sprint(key.Interface()))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func joinSlice(separator string, slice any) (string, error) {
+	value := reflect.ValueOf(slice)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return "", fmt.Errorf("join expects a slice, got %T", slice)
+	}
+	items := make([]string, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		items = append(items, fmt.Sprint(value.Index(i).Interface()))
+	}
+	return strings.Join(items, separator), nil
+}
@@ -0,0 +1,42 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_templateFuncMap(t *testing.T) {
+	assert.Equal(t, "awsVpcEndpoint", toCamelCase("aws_vpc_endpoint"))
+	assert.Equal(t, "AwsVpcEndpoint", toPascalCase("aws_vpc_endpoint"))
+	assert.Equal(t, "AwsEc2Host", toPascalCase("aws_ec2_host"))
+	assert.Equal(t, "aws_vpc_id", toSnakeCase("AwsVpcID"))
+	assert.Equal(t, "http_server", toSnakeCase("HTTPServer"))
+	assert.Equal(t, "_2fa_type", toGoIdent("2fa-type"))
+	assert.Equal(t, "type_", toGoIdent("type"))
+	assert.Equal(t, "`a \"b\"`", toBacktickLiteral(`a "b"`))
+	assert.Equal(t, "\"a `b`\\n\"", toBacktickLiteral("a `b`\n"))
+	assert.Equal(t, "\t\ta\n\n\t\tb", indentLines(2, "a\n\nb"))
+	assert.Equal(t, "// a\n//\n// b", commentLines("a\n\nb\n"))
+
+	keys, err := sortedKeys(map[string]struct{}{"b": {}, "a": {}, "c": {}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+
+	joined, err := joinSlice(", ", []string{"id", "arn"})
+	assert.Nil(t, err)
+	assert.Equal(t, "id, arn", joined)
+}
+
+func Test_newTemplate(t *testing.T) {
+	params := &SelefraTableSchemaRenderParams{
+		TableName:   "aws_vpc",
+		PrimaryKeys: []string{"id", "arn"},
+		ImportSet:   map[string]struct{}{"strings": {}, "context": {}},
+	}
+	tpl, err := newTemplate("test").Parse(`{{pascal .TableName}} {{join "," .PrimaryKeys}}{{range $key := sortedKeys .ImportSet}} {{quote $key}}{{end}}`)
+	assert.Nil(t, err)
+	buff := bytes.Buffer{}
+	assert.Nil(t, tpl.Execute(&buff, params))
+	assert.Equal(t, `AwsVpc id,arn "context" "strings"`, buff.String())
+}
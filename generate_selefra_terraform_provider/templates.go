@@ -0,0 +1,298 @@
+package generate_selefra_terraform_provider
+
+import (
+	"fmt"
+	"github.com/selefra/selefra-terraform-provider-scaffolding/provider_template/provider_template_v2_generate"
+	"github.com/selefra/selefra-terraform-provider-scaffolding/provider_template/provider_template_v2_init"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// The file names of the templates, a file with the same name in output.templates-dir overrides the embedded one
+const (
+	SelefraSchemaTemplateName        = "selefra_schema.go.tpl"
+	SelefraTableSchemaTemplateName   = "selefra_table_schema.go.tpl"
+	SelefraTableTemplateName         = "selefra_table.go.tpl"
+	SelefraTableRegistryTemplateName = "selefra_table_registry.go.tpl"
+	SelefraProviderTemplateName      = "selefra_provider.go.tpl"
+	SelefraProviderTestTemplateName  = "selefra_provider_test.go.tpl"
+	MainTemplateName                 = "main.go.tpl"
+	InitProviderTemplateName         = "provider.go.tpl"
+	InitClientTemplateName           = "client.go.tpl"
+	InitProviderTestTemplateName     = "provider_test.go.tpl"
+	InitProviderConfigTemplateName   = "provider_config.go.tpl"
+	InitTerraformBridgeTemplateName  = "terraform_bridge.go.tpl"
+	InitResourceListerTemplateName   = "resource_lister.go.tpl"
+	GoModTemplateName                = "go.mod.tpl"
+	NewProjectConfigTemplateName     = "config.yml.tpl"
+)
+
+// TemplateDefinition A template the scaffold renders, with the parameters it is rendered with
+type TemplateDefinition struct {
+
+	// The file name of the template
+	Name string
+
+	// The embedded content, used when the template is not overridden
+	DefaultContent string
+
+	// The value the template is executed with, only its type matters, it is used to check the fields a template references
+	RenderParams any
+}
+
+// GetTemplateDefinitions All templates that can be overridden, in dictionary order
+func GetTemplateDefinitions() []*TemplateDefinition {
+	definitions := []*TemplateDefinition{
+		{Name: SelefraSchemaTemplateName, DefaultContent: provider_template_v2_generate.SelefraSchemaTemplate, RenderParams: &SelefraProviderRenderParams{}},
+		{Name: SelefraTableSchemaTemplateName, DefaultContent: provider_template_v2_generate.SelefraTableSchemaTemplate, RenderParams: &SelefraTableSchemaRenderParams{}},
+		{Name: SelefraTableTemplateName, DefaultContent: provider_template_v2_generate.SelefraTableTemplate, RenderParams: &SelefraTableSchemaRenderParams{}},
+		{Name: SelefraTableRegistryTemplateName, DefaultContent: provider_template_v2_generate.SelefraTableRegistryTemplate, RenderParams: &SelefraProviderRenderParams{}},
+		{Name: SelefraProviderTemplateName, DefaultContent: provider_template_v2_generate.SelefraProviderTemplate, RenderParams: &SelefraProviderRenderParams{}},
+		{Name: SelefraProviderTestTemplateName, DefaultContent: provider_template_v2_generate.SelefraProviderTestTemplate, RenderParams: &SelefraProviderRenderParams{}},
+		{Name: MainTemplateName, DefaultContent: provider_template_v2_generate.MainTemplate, RenderParams: &MainRenderParams{}},
+		{Name: InitProviderTemplateName, DefaultContent: provider_template_v2_init.ProviderTemplate, RenderParams: &InitProviderGoRenderParams{}},
+		{Name: InitClientTemplateName, DefaultContent: provider_template_v2_init.ClientTemplate, RenderParams: &InitProviderGoRenderParams{}},
+		{Name: InitProviderTestTemplateName, DefaultContent: provider_template_v2_init.ProviderTestTemplate, RenderParams: &InitProviderGoRenderParams{}},
+		{Name: InitProviderConfigTemplateName, DefaultContent: provider_template_v2_init.ProviderConfigTemplate, RenderParams: &InitProviderGoRenderParams{}},
+		{Name: InitTerraformBridgeTemplateName, DefaultContent: provider_template_v2_init.TerraformBridgeTemplate, RenderParams: &InitProviderGoRenderParams{}},
+		{Name: InitResourceListerTemplateName, DefaultContent: provider_template_v2_init.ResourceListerTemplate, RenderParams: &InitProviderGoRenderParams{}},
+		{Name: GoModTemplateName, DefaultContent: provider_template_v2_generate.GoModTemplate, RenderParams: &GoModRenderParams{}},
+		{Name: NewProjectConfigTemplateName, DefaultContent: provider_template_v2_init.ConfigTemplate, RenderParams: &NewProjectConfigRenderParams{}},
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+func getTemplateDefinition(name string) *TemplateDefinition {
+	for _, definition := range GetTemplateDefinitions() {
+		if definition.Name == name {
+			return definition
+		}
+	}
+	return nil
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// LoadTemplate Parse the templates with the given names into one set, so that they can use each other's definitions,
+// a template found in output.templates-dir wins over the embedded one and is checked before it is used
+func (x *Config) LoadTemplate(names ...string) (*template.Template, error) {
+	var t *template.Template
+	for _, name := range names {
+		definition := getTemplateDefinition(name)
+		if definition == nil {
+			return nil, fmt.Errorf("%w: unknown template %s", ErrTemplate, name)
+		}
+		content, isOverride, err := x.readTemplateContent(definition)
+		if err != nil {
+			return nil, err
+		}
+		if isOverride {
+			if err := checkTemplateContent(definition, content); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrTemplate, err.Error())
+			}
+		}
+		if t == nil {
+			t = newTemplate(name)
+		} else {
+			t = t.New(name)
+		}
+		if _, err := t.Parse(content); err != nil {
+			return nil, fmt.Errorf("%w: parse template %s error: %s", ErrTemplate, name, err.Error())
+		}
+	}
+	return t.Lookup(names[0]), nil
+}
+
+func (x *Config) readTemplateContent(definition *TemplateDefinition) (content string, isOverride bool, err error) {
+	if x.Output.TemplatesDirectory == "" {
+		return definition.DefaultContent, false, nil
+	}
+	overridePath := filepath.Join(x.Output.TemplatesDirectory, definition.Name)
+	if exists, err := x.GetFileSystem().Exists(overridePath); err != nil || !exists {
+		return definition.DefaultContent, false, err
+	}
+	contentBytes, err := x.GetFileSystem().ReadFile(overridePath)
+	if err != nil {
+		return "", false, err
+	}
+	x.GetLogger().Info("template %s is overridden by %s", definition.Name, overridePath)
+	return string(contentBytes), true, nil
+}
+
+// ExportTemplates Write the embedded templates into the directory so that they can be edited and used as overrides,
+// existing files are only replaced when force is set
+func ExportTemplates(directory string, force bool) ([]string, error) {
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return nil, err
+	}
+	exportPathSlice := make([]string, 0)
+	for _, definition := range GetTemplateDefinitions() {
+		exportPath := filepath.Join(directory, definition.Name)
+		if exists, err := PathExists(exportPath); err != nil {
+			return exportPathSlice, err
+		} else if exists && !force {
+			return exportPathSlice, fmt.Errorf("template %s already exists, use force to overwrite it", exportPath)
+		}
+		if err := os.WriteFile(exportPath, []byte(definition.DefaultContent), 0644); err != nil {
+			return exportPathSlice, err
+		}
+		exportPathSlice = append(exportPathSlice, exportPath)
+	}
+	return exportPathSlice, nil
+}
+
+// CheckTemplatesDirectory Check every template in the directory, a file that does not override anything is an error too,
+// because a typo in its name would otherwise silently fall back to the embedded template
+func CheckTemplatesDirectory(directory string) error {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return err
+	}
+	problems := make([]string, 0)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		definition := getTemplateDefinition(entry.Name())
+		if definition == nil {
+			problems = append(problems, fmt.Sprintf("%s does not override any template", filepath.Join(directory, entry.Name())))
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(directory, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := checkTemplateContent(definition, string(content)); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) != 0 {
+		return fmt.Errorf("%w: templates in %s are invalid:\n\t%s", ErrTemplate, directory, strings.Join(problems, "\n\t"))
+	}
+	return nil
+}
+
+// The template is parsed on its own, so that only the fields it references itself are checked against its render params
+func checkTemplateContent(definition *TemplateDefinition, content string) error {
+	t, err := newTemplate(definition.Name).Parse(content)
+	if err != nil {
+		return err
+	}
+	return CheckTemplateFields(t, definition.RenderParams)
+}
+
+// CheckTemplateFields Make sure that every field the template and its definitions reference exists somewhere on the render
+// params, so that a template written against an older version of the scaffold fails here instead of rendering nothing
+func CheckTemplateFields(t *template.Template, renderParams any) error {
+	fieldSet := make(map[string]struct{})
+	collectFieldNames(reflect.TypeOf(renderParams), fieldSet, make(map[reflect.Type]struct{}))
+
+	problems := make([]string, 0)
+	for _, associated := range t.Templates() {
+		if associated.Tree == nil || associated.Tree.Root == nil {
+			continue
+		}
+		tree := associated.Tree
+		walkTemplateNode(tree.Root, func(node parse.Node, fieldNames []string) {
+			for _, fieldName := range fieldNames {
+				if _, exists := fieldSet[fieldName]; exists {
+					continue
+				}
+				location, _ := tree.ErrorContext(node)
+				problems = append(problems, fmt.Sprintf("%s: field %s does not exist on %s", location, fieldName, reflect.TypeOf(renderParams).String()))
+			}
+		})
+	}
+	if len(problems) != 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "\n\t"))
+	}
+	return nil
+}
+
+// The names of all fields and methods reachable from the type, a template may reach any of them through range or with
+func collectFieldNames(t reflect.Type, fieldSet map[string]struct{}, visited map[reflect.Type]struct{}) {
+	if t == nil {
+		return
+	}
+	if _, exists := visited[t]; exists {
+		return
+	}
+	visited[t] = struct{}{}
+	for i := 0; i < t.NumMethod(); i++ {
+		fieldSet[t.Method(i).Name] = struct{}{}
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		collectFieldNames(t.Elem(), fieldSet, visited)
+	case reflect.Map:
+		collectFieldNames(t.Key(), fieldSet, visited)
+		collectFieldNames(t.Elem(), fieldSet, visited)
+	case reflect.Struct:
+		collectFieldNames(reflect.PointerTo(t), fieldSet, visited)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldSet[field.Name] = struct{}{}
+			collectFieldNames(field.Type, fieldSet, visited)
+		}
+	}
+}
+
+func walkTemplateNode(node parse.Node, visit func(node parse.Node, fieldNames []string)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkTemplateNode(child, visit)
+		}
+	case *parse.ActionNode:
+		walkTemplateNode(n.Pipe, visit)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, command := range n.Cmds {
+			walkTemplateNode(command, visit)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			walkTemplateNode(arg, visit)
+		}
+	case *parse.FieldNode:
+		visit(n, n.Ident)
+	case *parse.VariableNode:
+		// $table.TableName, the variable itself is not a field
+		if len(n.Ident) > 1 {
+			visit(n, n.Ident[1:])
+		}
+	case *parse.ChainNode:
+		walkTemplateNode(n.Node, visit)
+		visit(n, n.Field)
+	case *parse.IfNode:
+		walkTemplateBranch(&n.BranchNode, visit)
+	case *parse.RangeNode:
+		walkTemplateBranch(&n.BranchNode, visit)
+	case *parse.WithNode:
+		walkTemplateBranch(&n.BranchNode, visit)
+	case *parse.TemplateNode:
+		walkTemplateNode(n.Pipe, visit)
+	}
+}
+
+func walkTemplateBranch(n *parse.BranchNode, visit func(node parse.Node, fieldNames []string)) {
+	walkTemplateNode(n.Pipe, visit)
+	walkTemplateNode(n.List, visit)
+	walkTemplateNode(n.ElseList, visit)
+}
@@ -0,0 +1,68 @@
+package generate_selefra_terraform_provider
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportTemplates(t *testing.T) {
+	directory := t.TempDir()
+
+	exportPathSlice, err := ExportTemplates(directory, false)
+	assert.Nil(t, err)
+	assert.Equal(t, len(GetTemplateDefinitions()), len(exportPathSlice))
+
+	// the embedded templates are valid overrides of themselves
+	assert.Nil(t, CheckTemplatesDirectory(directory))
+
+	// exporting again does not silently overwrite the edited templates
+	_, err = ExportTemplates(directory, false)
+	assert.NotNil(t, err)
+	_, err = ExportTemplates(directory, true)
+	assert.Nil(t, err)
+}
+
+func TestCheckTemplatesDirectory(t *testing.T) {
+
+	// case 001. a field that does not exist on the render params
+	directory := t.TempDir()
+	content := "package main\n\n// {{.ModuleName}}\n{{range $index, $table := .TableSlice}}{{$table.TableNameTypo}}{{end}}\n"
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, SelefraProviderTemplateName), []byte(content), 0644))
+	err := CheckTemplatesDirectory(directory)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "selefra_provider.go.tpl:4")
+	assert.Contains(t, err.Error(), "TableNameTypo")
+	assert.NotContains(t, err.Error(), "ModuleName")
+
+	// case 002. a file that does not override anything
+	directory = t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, "selefra_providers.go.tpl"), []byte(""), 0644))
+	err = CheckTemplatesDirectory(directory)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "does not override any template")
+}
+
+func TestConfig_LoadTemplate(t *testing.T) {
+	directory := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(directory, MainTemplateName), []byte("package main // {{.ModuleName}}\n"), 0644))
+	config := &Config{
+		Output: Output{
+			TemplatesDirectory: directory,
+		},
+	}
+
+	// case 001. the override wins over the embedded template
+	mainTemplate, err := config.LoadTemplate(MainTemplateName)
+	assert.Nil(t, err)
+	buff := bytes.Buffer{}
+	assert.Nil(t, mainTemplate.Execute(&buff, &MainRenderParams{ModuleName: "github.com/selefra/foo"}))
+	assert.Equal(t, "package main // github.com/selefra/foo\n", buff.String())
+
+	// case 002. templates that are not overridden fall back to the embedded ones
+	providerTemplate, err := config.LoadTemplate(SelefraProviderTemplateName)
+	assert.Nil(t, err)
+	assert.NotNil(t, providerTemplate)
+}
@@ -0,0 +1,241 @@
+package generate_selefra_terraform_provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DefaultTerraformRegistryUrl The public Terraform registry, any registry that speaks the same API can be used instead
+const DefaultTerraformRegistryUrl = "https://registry.terraform.io"
+
+// DefaultOpenTofuRegistryUrl The registry of OpenTofu, it speaks the same provider registry protocol
+const DefaultOpenTofuRegistryUrl = "https://registry.opentofu.org"
+
+// TerraformRegistry Find providers and their versions in a Terraform registry
+type TerraformRegistry struct {
+	baseUrl    string
+	httpClient *HttpClient
+}
+
+func NewTerraformRegistry(baseUrl string) *TerraformRegistry {
+	if baseUrl == "" {
+		baseUrl = DefaultTerraformRegistryUrl
+	}
+	return &TerraformRegistry{
+		baseUrl:    strings.TrimRight(baseUrl, "/"),
+		httpClient: defaultHttpClient,
+	}
+}
+
+// SetHttpClient Send the requests through the given client
+func (x *TerraformRegistry) SetHttpClient(httpClient *HttpClient) *TerraformRegistry {
+	x.httpClient = httpClient
+	return x
+}
+
+// TerraformRegistryProvider A provider as the registry lists it
+type TerraformRegistryProvider struct {
+
+	// hashicorp
+	Namespace string
+
+	// aws
+	Name string
+
+	Description string
+
+	// official, partner or community
+	Tier string
+
+	Downloads int64
+
+	// The repository of the provider, https://github.com/hashicorp/terraform-provider-aws
+	Source string
+
+	// The latest version, only set by GetProvider
+	Version string
+
+	// All published versions, only set by GetProvider
+	Versions []string
+}
+
+// FullName hashicorp/aws
+func (x *TerraformRegistryProvider) FullName() string {
+	return x.Namespace + "/" + x.Name
+}
+
+// GetRepoUrl The repository to use as terraform.provider.repo-url, the GitHub convention is assumed when the registry does not know it
+func (x *TerraformRegistryProvider) GetRepoUrl() string {
+	if x.Source != "" {
+		return strings.TrimSuffix(x.Source, ".git")
+	}
+	return fmt.Sprintf("https://github.com/%s/terraform-provider-%s", x.Namespace, x.Name)
+}
+
+// SearchProviders The providers that match the query, most downloaded first as the registry orders them
+func (x *TerraformRegistry) SearchProviders(query string, limit int) ([]*TerraformRegistryProvider, error) {
+	response, err := x.httpClient.R(context.Background()).
+		SetQueryParam("filter[query]", query).
+		SetQueryParam("page[size]", fmt.Sprintf("%d", limit)).
+		Get(x.baseUrl + "/v2/providers")
+	if err != nil {
+		return nil, fmt.Errorf("%w: search terraform registry %s error: %s", ErrNetwork, x.baseUrl, err.Error())
+	}
+	if !response.IsSuccess() {
+		return nil, fmt.Errorf("%w: search terraform registry %s failed, status = %s", ErrNetwork, x.baseUrl, response.Status())
+	}
+
+	r := &struct {
+		Data []struct {
+			Attributes struct {
+				Namespace   string `json:"namespace"`
+				Name        string `json:"name"`
+				Description string `json:"description"`
+				Tier        string `json:"tier"`
+				Downloads   int64  `json:"downloads"`
+				Source      string `json:"source"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(response.Body(), r); err != nil {
+		return nil, fmt.Errorf("terraform registry search response json unmarshal failed: %s", err.Error())
+	}
+	providers := make([]*TerraformRegistryProvider, 0, len(r.Data))
+	for _, item := range r.Data {
+		providers = append(providers, &TerraformRegistryProvider{
+			Namespace:   item.Attributes.Namespace,
+			Name:        item.Attributes.Name,
+			Description: item.Attributes.Description,
+			Tier:        item.Attributes.Tier,
+			Downloads:   item.Attributes.Downloads,
+			Source:      item.Attributes.Source,
+		})
+	}
+	return providers, nil
+}
+
+// GetProvider The provider with its latest version and all published versions
+func (x *TerraformRegistry) GetProvider(namespace, name string) (*TerraformRegistryProvider, error) {
+	targetUrl := fmt.Sprintf("%s/v1/providers/%s/%s", x.baseUrl, url.PathEscape(namespace), url.PathEscape(name))
+	response, err := x.httpClient.R(context.Background()).Get(targetUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: request %s error: %s", ErrNetwork, targetUrl, err.Error())
+	}
+	if !response.IsSuccess() {
+		return nil, fmt.Errorf("%w: request %s failed, status = %s", ErrNetwork, targetUrl, response.Status())
+	}
+
+	r := &struct {
+		Namespace   string   `json:"namespace"`
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Tier        string   `json:"tier"`
+		Downloads   int64    `json:"downloads"`
+		Source      string   `json:"source"`
+		Version     string   `json:"version"`
+		Versions    []string `json:"versions"`
+	}{}
+	if err := json.Unmarshal(response.Body(), r); err != nil {
+		return nil, fmt.Errorf("terraform registry provider response json unmarshal failed: %s", err.Error())
+	}
+	return &TerraformRegistryProvider{
+		Namespace:   r.Namespace,
+		Name:        r.Name,
+		Description: r.Description,
+		Tier:        r.Tier,
+		Downloads:   r.Downloads,
+		Source:      r.Source,
+		Version:     r.Version,
+		Versions:    r.Versions,
+	}, nil
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// TerraformRegistryVersion A version of a provider and the platforms it is built for, as the provider registry protocol
+// lists them
+type TerraformRegistryVersion struct {
+	Version   string `json:"version"`
+	Platforms []struct {
+		OS   string `json:"os"`
+		Arch string `json:"arch"`
+	} `json:"platforms"`
+}
+
+// TerraformRegistryDownload Where the build of a version for a platform is downloaded from
+type TerraformRegistryDownload struct {
+	DownloadUrl string `json:"download_url"`
+	Shasum      string `json:"shasum"`
+}
+
+// Where the provider registry protocol is served, the registry tells it through service discovery
+func (x *TerraformRegistry) discoverProvidersPath() (string, error) {
+	targetUrl := x.baseUrl + "/.well-known/terraform.json"
+	response, err := x.httpClient.R(context.Background()).Get(targetUrl)
+	if err != nil {
+		return "", fmt.Errorf("%w: request %s error: %s", ErrNetwork, targetUrl, err.Error())
+	}
+	if !response.IsSuccess() {
+		return "", fmt.Errorf("%w: request %s failed, status = %s", ErrNetwork, targetUrl, response.Status())
+	}
+	r := &struct {
+		ProvidersV1 string `json:"providers.v1"`
+	}{}
+	if err := json.Unmarshal(response.Body(), r); err != nil {
+		return "", fmt.Errorf("%w: service discovery response of %s json unmarshal failed: %s", ErrNetwork, targetUrl, err.Error())
+	}
+	if r.ProvidersV1 == "" {
+		return "", fmt.Errorf("%w: %s does not serve the provider registry protocol", ErrCheckConfigFailed, x.baseUrl)
+	}
+	// It may be relative to the registry or a url of its own
+	if strings.Contains(r.ProvidersV1, "://") {
+		return strings.TrimRight(r.ProvidersV1, "/") + "/", nil
+	}
+	return x.baseUrl + "/" + strings.Trim(r.ProvidersV1, "/") + "/", nil
+}
+
+// ListVersions The versions of the provider with their platforms, through the provider registry protocol
+func (x *TerraformRegistry) ListVersions(namespace, providerType string) ([]*TerraformRegistryVersion, error) {
+	providersUrl, err := x.discoverProvidersPath()
+	if err != nil {
+		return nil, err
+	}
+	r := &struct {
+		Versions []*TerraformRegistryVersion `json:"versions"`
+	}{}
+	if err := x.getJson(providersUrl+url.PathEscape(namespace)+"/"+url.PathEscape(providerType)+"/versions", r); err != nil {
+		return nil, err
+	}
+	return r.Versions, nil
+}
+
+// GetDownload The build of the version for the platform, through the provider registry protocol
+func (x *TerraformRegistry) GetDownload(namespace, providerType, version, os, arch string) (*TerraformRegistryDownload, error) {
+	providersUrl, err := x.discoverProvidersPath()
+	if err != nil {
+		return nil, err
+	}
+	download := &TerraformRegistryDownload{}
+	targetUrl := fmt.Sprintf("%s%s/%s/%s/download/%s/%s", providersUrl, url.PathEscape(namespace), url.PathEscape(providerType), url.PathEscape(version), os, arch)
+	if err := x.getJson(targetUrl, download); err != nil {
+		return nil, err
+	}
+	return download, nil
+}
+
+func (x *TerraformRegistry) getJson(targetUrl string, v any) error {
+	response, err := x.httpClient.R(context.Background()).Get(targetUrl)
+	if err != nil {
+		return fmt.Errorf("%w: request %s error: %s", ErrNetwork, targetUrl, err.Error())
+	}
+	if !response.IsSuccess() {
+		return fmt.Errorf("%w: request %s failed, status = %s", ErrNetwork, targetUrl, response.Status())
+	}
+	if err := json.Unmarshal(response.Body(), v); err != nil {
+		return fmt.Errorf("%w: response of %s json unmarshal failed: %s", ErrNetwork, targetUrl, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package generate_selefra_terraform_provider
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTerraformRegistry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers":
+			assert.Equal(t, "aws", r.URL.Query().Get("filter[query]"))
+			assert.Equal(t, "5", r.URL.Query().Get("page[size]"))
+			_, _ = w.Write([]byte(`{"data": [{"attributes": {"namespace": "hashicorp", "name": "aws", "tier": "official", "downloads": 42, "source": "https://github.com/hashicorp/terraform-provider-aws"}}]}`))
+		case "/v1/providers/hashicorp/aws":
+			_, _ = w.Write([]byte(`{"namespace": "hashicorp", "name": "aws", "version": "4.50.0", "versions": ["4.49.0", "4.50.0"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	registry := NewTerraformRegistry(server.URL + "/")
+
+	providers, err := registry.SearchProviders("aws", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(providers))
+	assert.Equal(t, "hashicorp/aws", providers[0].FullName())
+	assert.Equal(t, int64(42), providers[0].Downloads)
+	assert.Equal(t, "https://github.com/hashicorp/terraform-provider-aws", providers[0].GetRepoUrl())
+
+	registryProvider, err := registry.GetProvider("hashicorp", "aws")
+	assert.Nil(t, err)
+	assert.Equal(t, "4.50.0", registryProvider.Version)
+	assert.Equal(t, []string{"4.49.0", "4.50.0"}, registryProvider.Versions)
+	// the GitHub convention is assumed without a source
+	assert.Equal(t, "https://github.com/hashicorp/terraform-provider-aws", registryProvider.GetRepoUrl())
+
+	_, err = registry.GetProvider("hashicorp", "nope")
+	assert.ErrorIs(t, err, ErrNetwork)
+}
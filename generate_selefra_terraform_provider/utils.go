@@ -1,6 +1,10 @@
 package generate_selefra_terraform_provider
 
 import (
+	"errors"
+	"fmt"
+	"go/scanner"
+	"golang.org/x/tools/imports"
 	"os"
 	"strings"
 )
@@ -16,41 +20,32 @@ func PathExists(path string) (bool, error) {
 	return false, err
 }
 
-func escapeStringForQuote(s string) string {
-	buff := strings.Builder{}
-	for index, char := range s {
-		if char == '"' && index > 0 && s[index-1] != '\\' {
-			buff.WriteString("\\")
-		} else if char == '\\' && index > 0 && s[index-1] != '\\' {
-			buff.WriteString("\\")
-		}
-		buff.WriteRune(char)
-	}
-	return buff.String()
-}
-
 // ------------------------------------------------- --------------------------------------------------------------------
 
-func processDescription(s string) string {
-
-	if s == "" {
-		return ""
+// formatGoSource Every go file the scaffold emits goes through here, so it is formatted the way gofmt/goimports would
+// format it, and a template that renders broken go code fails at generation time instead of at the user's go build
+func formatGoSource(filename string, src []byte) ([]byte, error) {
+	formatted, err := imports.Process(filename, src, &imports.Options{
+		Comments:   true,
+		TabIndent:  true,
+		TabWidth:   8,
+		FormatOnly: true,
+	})
+	if err == nil {
+		return formatted, nil
 	}
 
-	if strings.Contains(s, "`") {
-		buff := strings.Builder{}
-		buff.WriteRune('"')
-		buff.WriteString(escapeStringForQuote(s))
-		buff.WriteRune('"')
-		return buff.String()
-	} else {
-		//buff := strings.Builder{}
-		//buff.WriteRune('`')
-		//buff.WriteString(s)
-		//buff.WriteRune('`')
-		//return buff.String()
-		return "`" + s + "`"
+	// The file is never written when it can not be parsed, so show the offending line to make the template easy to fix
+	var errorList scanner.ErrorList
+	if errors.As(err, &errorList) && len(errorList) > 0 {
+		line := errorList[0].Pos.Line
+		lines := strings.Split(string(src), "\n")
+		if line >= 1 && line <= len(lines) {
+			return nil, fmt.Errorf("%w: rendered go source does not parse: %s\n\t%d | %s", ErrParse, errorList[0].Error(), line, lines[line-1])
+		}
+		return nil, fmt.Errorf("%w: rendered go source does not parse: %s", ErrParse, errorList[0].Error())
 	}
+	return nil, fmt.Errorf("%w: rendered go source does not parse: %s: %s", ErrParse, filename, err.Error())
 }
 
 // ------------------------------------------------- --------------------------------------------------------------------
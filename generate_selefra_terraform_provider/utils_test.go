@@ -5,37 +5,19 @@ import (
 	"testing"
 )
 
-func Test_escapeStringForQuote(t *testing.T) {
-	s := "asdasdasdasdasdasd\""
-	quote := escapeStringForQuote(s)
-	t.Log(quote)
+func Test_formatGoSource(t *testing.T) {
+
+	// case 001. imports are sorted and the indentation is fixed
+	src := "package resources\n\nimport (\n\"strings\"\n    \"context\"\n)\n\nfunc foo(ctx context.Context) string {\n        return strings.TrimSpace(\" \")\n}\n"
+	excepted := "package resources\n\nimport (\n\t\"context\"\n\t\"strings\"\n)\n\nfunc foo(ctx context.Context) string {\n\treturn strings.TrimSpace(\" \")\n}\n"
+	formatted, err := formatGoSource("foo.go", []byte(src))
+	assert.Nil(t, err)
+	assert.Equal(t, excepted, string(formatted))
+
+	// case 002. the error points to the file and line that does not parse
+	src = "package resources\n\nfunc foo() {\n\treturn \"\n}\n"
+	_, err = formatGoSource("resources/foo.go", []byte(src))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "resources/foo.go:4:")
+	assert.Contains(t, err.Error(), "4 | \treturn \"")
 }
-
-func Test_processDescription(t *testing.T) {
-
-	// case 001. normal string
-	s := "hello, world"
-	excepted := "`hello, world`"
-	answer := processDescription(s)
-	assert.Equal(t, excepted, answer)
-
-	// case 002.
-	s = "hello', world\" \\"
-	excepted = "`hello', world\" \\`"
-	answer = processDescription(s)
-	assert.Equal(t, excepted, answer)
-
-	// case 003.
-	s = "hello',`"
-	excepted = "\"hello',`\""
-	answer = processDescription(s)
-	assert.Equal(t, excepted, answer)
-
-	// case 004.
-	s = `\n\n\n\n`
-	excepted = "`\\n\\n\\n\\n`"
-	answer = processDescription(s)
-	assert.Equal(t, excepted, answer)
-
-}
-
@@ -5,6 +5,15 @@ import _ "embed"
 //go:embed selefra_schema.go.tpl
 var SelefraSchemaTemplate string
 
+//go:embed selefra_table_schema.go.tpl
+var SelefraTableSchemaTemplate string
+
+//go:embed selefra_table.go.tpl
+var SelefraTableTemplate string
+
+//go:embed selefra_table_registry.go.tpl
+var SelefraTableRegistryTemplate string
+
 //go:embed selefra_provider.go.tpl
 var SelefraProviderTemplate string
 
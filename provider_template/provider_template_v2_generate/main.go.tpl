@@ -3,6 +3,13 @@
 package main
 
 import (
+{{- if .TerraformBridgeContext}}
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+{{- end}}
+
 	"github.com/selefra/selefra-provider-sdk/grpc/serve"
 	"{{.ModuleName}}/resources"
 )
@@ -10,6 +17,23 @@ import (
 func main() {
 
 	myProvider := resources.GetSelefraProvider()
+{{- if .TerraformBridgeContext}}
+
+	// The terraform providers the clients start live as long as this context, it is cancelled when the provider is
+	// interrupted or terminated, and when it exits
+	ctx, cancel := context.WithCancel(context.Background())
+	resources.SetTerraformBridgeContext(ctx)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+		resources.ShutdownTerraformBridges()
+		os.Exit(0)
+	}()
+	defer resources.ShutdownTerraformBridges()
+	defer cancel()
+{{- end}}
 	serve.Serve(myProvider.Name, myProvider)
 
 }
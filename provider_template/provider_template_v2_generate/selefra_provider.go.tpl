@@ -6,40 +6,57 @@ import (
 	"context"
 	"github.com/selefra/selefra-provider-sdk/provider"
 	"github.com/selefra/selefra-provider-sdk/provider/schema"
-	"github.com/selefra/selefra-provider-sdk/terraform/bridge"
+	"github.com/selefra/selefra-provider-sdk/terraform/bridge"{{if .MultiClient}}
+	"github.com/selefra/selefra-provider-sdk/provider/transformer/column_value_extractor"{{end}}
 )
 
 func GetSelefraProvider() *provider.Provider {
 	diagnostics := schema.NewDiagnostics()
-	selefraProvider, d := GetSelefraTerraformProvider().ToSelefraProvider(func(ctx context.Context, clientMeta *schema.ClientMeta, taskClient any, task *schema.DataSourcePullTask) *bridge.TerraformBridge {
-		return taskClient.(*Client).TerraformBridge
-	})
+	selefraProvider, d := GetSelefraTerraformProvider().ToSelefraProvider(getTerraformBridge)
 	if diagnostics.AddDiagnostics(d).HasError() {
 		panic(diagnostics.ToString())
 	}
 
-    selefraProvider.TableList = GetSelefraTables()
+	selefraProvider.TableList = GetSelefraTables()
 
 	return selefraProvider
 }
 
 func GetSelefraTables() []*schema.Table {
 
-    diagnostics := schema.NewDiagnostics()
-    tables := make([]*schema.Table, 0)
-    var table *schema.Table
-    var d *schema.Diagnostics
-
-    {{range $index, $table := .TableSlice}}
-    table, d = TableSchemaGenerator_{{$table.TableName}}()
-    if !diagnostics.AddDiagnostics(d).HasError() {
-        tables = append(tables, table)
-    }
-    {{end}}
+	diagnostics := schema.NewDiagnostics()
+	tables := make([]*schema.Table, 0)
+	var table *schema.Table
+	var d *schema.Diagnostics
+
+	{{range $index, $table := .TableSlice}}
+	table, d = TableSchemaGenerator_{{$table.TableName}}()
+	if !diagnostics.AddDiagnostics(d).HasError() {
+		tables = append(tables, table)
+	}
+	{{end}}
 
-    if diagnostics.HasError() {
-        panic(diagnostics.ToString())
-    }
+	if diagnostics.HasError() {
+		panic(diagnostics.ToString())
+	}
 
 	return tables
 }
+
+// The bridge to run a task with, a client that restarts its terraform provider when it crashed gives the running one
+func getTerraformBridge(ctx context.Context, clientMeta *schema.ClientMeta, taskClient any, task *schema.DataSourcePullTask) *bridge.TerraformBridge {
+	if managedClient, ok := taskClient.(interface {
+		GetTerraformBridge(ctx context.Context, clientMeta *schema.ClientMeta) *bridge.TerraformBridge
+	}); ok {
+		return managedClient.GetTerraformBridge(ctx, clientMeta)
+	}
+	return taskClient.(*Client).TerraformBridge
+}
+{{if .MultiClient}}
+// The value of the account_id or region column, the one of the client that pulled the row
+func clientColumnValueExtractor(getValue func(client *Client) string) schema.ColumnValueExtractor {
+	return column_value_extractor.WrapperExtractFunction(func(ctx context.Context, clientMeta *schema.ClientMeta, client any, task *schema.DataSourcePullTask, row *schema.Row, column *schema.Column, result any) (any, *schema.Diagnostics) {
+		return getValue(client.(*Client)), nil
+	})
+}
+{{end}}
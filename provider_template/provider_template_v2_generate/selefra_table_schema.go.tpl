@@ -0,0 +1,26 @@
+{{define "table_schema"}}
+{{if .Description}}{{comment .Description}}{{else}}// {{.TableName}}{{end}}
+func TableSchemaGenerator_{{.TableName}}() (*schema.Table, *schema.Diagnostics) {
+	diagnostics := schema.NewDiagnostics()
+
+	table, d := GetResource_{{.TableName}}().ToTable(getTerraformBridge)
+	if diagnostics.AddDiagnostics(d).HasError() {
+		return nil, diagnostics
+	}
+
+	table.Columns = GetColumns_{{.TableName}}()
+	if len(table.Columns) == 0 {
+		return nil, diagnostics.AddErrorMsg("")
+	}
+
+	return table, diagnostics
+}
+
+// {{.TableName}}
+func GetColumns_{{.TableName}}() []*schema.Column {
+	return []*schema.Column{ {{range $index, $column := .ColumnSchemaSlice}}
+		table_schema_generator.NewColumnBuilder().ColumnName({{quote $column.ColumnName}}).ColumnType({{$column.ColumnTypeCodeString}}){{if $column.Options.Unique}}.SetUnique(){{end}}{{if $column.Options.NotNull}}.SetNotNull(){{end}}{{if $column.Description}}.Description({{backtick $column.Description}}){{end}}{{if $column.ExtractorInlineCodeString}}.
+		Extractor({{$column.ExtractorInlineCodeString}}){{end}}.Build(), {{end}}
+	}
+}
+{{end}}
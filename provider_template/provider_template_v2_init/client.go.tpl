@@ -1,9 +1,41 @@
 package provider
 
-import "github.com/selefra/selefra-provider-sdk/terraform/bridge"
+import (
+	"context"
+
+	"github.com/selefra/selefra-provider-sdk/provider/schema"
+	"github.com/selefra/selefra-provider-sdk/terraform/bridge"
+)
 
 type Client struct {
+
+	// The bridge the terraform provider was started with, use GetTerraformBridge to get the running one
 	TerraformBridge *bridge.TerraformBridge
+{{- if .MultiClient}}
+
+	// The account and the region of the entry of clients this client was started for, the rows it pulls get them as
+	// the account_id and region columns
+	AccountID string
+	Region    string
+{{- end}}
+
+	// Restarts the terraform provider when it crashed, see terraform_bridge.go
+	managedBridge *managedTerraformBridge
 
 	// TODO You can continue to refine your client
 }
+
+// SetManagedTerraformBridge Let the client run its tasks with the terraform provider of the managed bridge
+func (x *Client) SetManagedTerraformBridge(managedBridge *managedTerraformBridge) {
+	x.managedBridge = managedBridge
+	x.TerraformBridge = managedBridge.bridge
+}
+
+// GetTerraformBridge The bridge to run a task with, the tables of the generated provider get it through this method,
+// the terraform provider is restarted first if it crashed
+func (x *Client) GetTerraformBridge(ctx context.Context, clientMeta *schema.ClientMeta) *bridge.TerraformBridge {
+	if x.managedBridge == nil {
+		return x.TerraformBridge
+	}
+	return x.managedBridge.GetBridge(ctx, clientMeta)
+}
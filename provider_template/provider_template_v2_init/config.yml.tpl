@@ -0,0 +1,55 @@
+# yaml-language-server: $schema=https://raw.githubusercontent.com/selefra/selefra-terraform-provider-scaffolding/main/generate_selefra_terraform_provider/config.schema.json
+selefra:
+  # The go module name of the selefra provider
+  module-name: {{quote .ModuleName}}
+terraform:
+  provider:
+    # Which provider of the terraform is being converted
+    repo-url: {{quote .RepoUrl}}
+{{- if .Version}}
+    # The version of the provider, remove it to always use the latest release
+    version: {{quote .Version}}
+{{- end}}
+    # When initializing the provider, you may need to perform some configuration to start it,
+    # string values may reference ${env:NAME} and ${file:PATH}
+    config: {}
+{{- if .Resources}}
+    # The resources to generate tables for, remove the list to generate all of them
+    resources:
+{{- range $resource := .Resources}}
+      - {{quote $resource}}
+{{- end}}
+{{- end}}
+output:
+  # Where to place the generated results, relative to the directory the scaffold runs in
+  directory: "./"
+{{- with .Network}}
+{{- if or .Proxy .NoProxy .CaFile .Timeout .Retries .RetryWait .RetryMaxWait}}
+# How the provider releases are reached
+network:
+{{- if .Proxy}}
+  proxy: {{quote .Proxy}}
+{{- end}}
+{{- if .NoProxy}}
+  no-proxy:
+{{- range $host := .NoProxy}}
+    - {{quote $host}}
+{{- end}}
+{{- end}}
+{{- if .CaFile}}
+  ca-file: {{quote .CaFile}}
+{{- end}}
+{{- if .Timeout}}
+  timeout: {{quote .Timeout}}
+{{- end}}
+{{- if .Retries}}
+  retries: {{.Retries}}
+{{- end}}
+{{- if .RetryWait}}
+  retry-wait: {{quote .RetryWait}}
+{{- end}}
+{{- if .RetryMaxWait}}
+  retry-max-wait: {{quote .RetryMaxWait}}
+{{- end}}
+{{- end}}
+{{- end}}
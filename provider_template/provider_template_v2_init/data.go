@@ -7,3 +7,21 @@ var ProviderTemplate string
 
 //go:embed resources.go.tpl
 var ResourceTemplate string
+
+//go:embed client.go.tpl
+var ClientTemplate string
+
+//go:embed config.yml.tpl
+var ConfigTemplate string
+
+//go:embed provider_test.go.tpl
+var ProviderTestTemplate string
+
+//go:embed provider_config.go.tpl
+var ProviderConfigTemplate string
+
+//go:embed terraform_bridge.go.tpl
+var TerraformBridgeTemplate string
+
+//go:embed resource_lister.go.tpl
+var ResourceListerTemplate string
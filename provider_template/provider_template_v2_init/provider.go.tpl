@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"github.com/selefra/selefra-provider-sdk/terraform/bridge"
 	terraform_providers "github.com/selefra/selefra-provider-sdk/terraform/provider"
 	"github.com/selefra/selefra-provider-sdk/terraform/selefra_terraform_schema"
 
@@ -14,16 +13,62 @@ import (
 const Version = "v0.0.1"
 
 func GetSelefraTerraformProvider() *selefra_terraform_schema.SelefraTerraformProvider {
+	// The terraform providers the clients of the last InitClient call started
+	terraformBridges := newTerraformBridgeSet()
 	return &selefra_terraform_schema.SelefraTerraformProvider{
-		Name:         "{{.SelefraProviderName}}",
+		Name:         {{quote .SelefraProviderName}},
 		Version:      Version,
 		ResourceList: getResources(),
 		ClientMeta: schema.ClientMeta{
+{{- if .MultiClient}}
+			InitClient: func(ctx context.Context, clientMeta *schema.ClientMeta, config *viper.Viper) ([]any, *schema.Diagnostics) {
+
+				diagnostics := schema.NewDiagnostics()
+
+				// the terraform providers of an earlier configuration are not used any more
+				terraformBridges.Shutdown()
+
+				// one client for every entry of clients in selefra provider's config file
+				clientConfigs, err := getClientConfigs(config)
+				if err != nil {
+					return nil, diagnostics.AddError(err)
+				}
+				clients := make([]any, 0, len(clientConfigs))
+				for _, clientConfig := range clientConfigs {
+					clients = append(clients, &Client{AccountID: clientConfig.AccountID, Region: clientConfig.Region})
+				}
+
+				// run terraform providers, each client has its own
+				if clientMeta.Runtime().Workspace != "" {
+					providerSaveDirectory := clientMeta.Runtime().Workspace + "/" + clientMeta.Runtime().ProviderName + "/" + clientMeta.Runtime().ProviderVersion
+					providerFileSlice := getTerraformProviderExecuteFileSlice()
+					providerExecFilePath, err := terraform_providers.NewProviderDownloader(providerFileSlice).Download(providerSaveDirectory)
+					if err != nil {
+						return nil, diagnostics.AddError(err)
+					}
+					for index, clientConfig := range clientConfigs {
+						managedBridge, err := terraformBridges.Start(providerExecFilePath, clientConfig.Config)
+						if err != nil {
+							// the providers that were started already are not used
+							terraformBridges.Shutdown()
+							return nil, diagnostics.AddError(err)
+						}
+						clients[index].(*Client).SetManagedTerraformBridge(managedBridge)
+					}
+				}
+
+				return clients, nil
+			},
+		},
+{{- else}}
 			InitClient: func(ctx context.Context, clientMeta *schema.ClientMeta, config *viper.Viper) ([]any, *schema.Diagnostics) {
 
 				diagnostics := schema.NewDiagnostics()
 				client := &Client{}
 
+				// the terraform provider of an earlier configuration is not used any more
+				terraformBridges.Shutdown()
+
 				// run terraform providers
 				if clientMeta.Runtime().Workspace != "" {
 					providerSaveDirectory := clientMeta.Runtime().Workspace + "/" + clientMeta.Runtime().ProviderName + "/" + clientMeta.Runtime().ProviderVersion
@@ -32,7 +77,6 @@ func GetSelefraTerraformProvider() *selefra_terraform_schema.SelefraTerraformPro
 					if err != nil {
 						return nil, diagnostics.AddError(err)
 					}
-					bridge := bridge.NewTerraformBridge(providerExecFilePath)
 
 					// read terraform config from selefra provider's config file
 					terraformProviderConfig := make(map[string]any, 0)
@@ -43,24 +87,24 @@ func GetSelefraTerraformProvider() *selefra_terraform_schema.SelefraTerraformPro
 						}
 					}
 
-					err = bridge.StartBridge(context.Background(), terraformProviderConfig)
+					// the terraform provider is probed before the tasks of the client and restarted when it crashed
+					managedBridge, err := terraformBridges.Start(providerExecFilePath, terraformProviderConfig)
 					if err != nil {
 						return nil, diagnostics.AddError(err)
 					}
-					client.TerraformBridge = bridge
+					client.SetManagedTerraformBridge(managedBridge)
 				}
 
 				return []any{client}, nil
 			},
 		},
+{{- end}}
 		ConfigMeta: provider.ConfigMeta{
 			GetDefaultConfigTemplate: func(ctx context.Context) string {
-				// TODO
-				return ``
+				return getDefaultConfigTemplate()
 			},
 			Validation: func(ctx context.Context, config *viper.Viper) *schema.Diagnostics {
-				// TODO
-				return nil
+				return validateProviderConfig(config)
 			},
 		},
 		TransformerMeta: schema.TransformerMeta{
@@ -78,11 +122,12 @@ func getTerraformProviderExecuteFileSlice() []*terraform_providers.TerraformProv
 
 	{{range $key, $value := .TerraformProviderExecuteFileSlice}}
     providerFileSlice = append(providerFileSlice, &terraform_providers.TerraformProviderFile{
-        ProviderName:    "{{$value.ProviderName}}",
-        ProviderVersion: "{{$value.ProviderVersion}}",
-        DownloadUrl:     "{{$value.DownloadUrl}}",
-        Arch:            "{{$value.Arch}}",
-        OS:              "{{$value.OS}}",
+        ProviderName:    {{quote $value.ProviderName}},
+        ProviderVersion: {{quote $value.ProviderVersion}},
+        DownloadUrl:     {{quote $value.DownloadUrl}},
+        Sha256Sum:       {{quote $value.Sha256Sum}},
+        Arch:            {{quote $value.Arch}},
+        OS:              {{quote $value.OS}},
     })
     {{end}}
 
@@ -0,0 +1,277 @@
+package provider
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/selefra/selefra-provider-sdk/provider/schema"
+	"github.com/spf13/viper"
+)
+
+// providerConfigArgument An argument of the provider block of the terraform provider, the configuration of the provider
+// is handed to the terraform provider as that block
+type providerConfigArgument struct {
+	Name string
+
+	// bool, int, float, string, list, set or map
+	Type string
+
+	// The type of the elements of a list, set or map of primitive values
+	ElemType string
+
+	Required bool
+
+	// The terraform provider has a default for it, from the environment for example, it may be left out even if it
+	// is required
+	HasDefault bool
+
+	// The arguments of a nested block, a block with MaxItems 1 may be written as one mapping
+	Arguments []*providerConfigArgument
+	MaxItems  int
+}
+
+{{define "providerConfigArguments"}}
+{{- range .}}
+{
+	Name: {{quote .Name}},
+	Type: {{quote .Type}},
+	{{- if .ElemType}}
+	ElemType: {{quote .ElemType}},
+	{{- end}}
+	{{- if .Required}}
+	Required: true,
+	{{- end}}
+	{{- if .HasDefault}}
+	HasDefault: true,
+	{{- end}}
+	{{- if .IsBlock}}
+	Arguments: []*providerConfigArgument{
+		{{- template "providerConfigArguments" .Arguments}}
+	},
+	{{- end}}
+	{{- if .MaxItems}}
+	MaxItems: {{.MaxItems}},
+	{{- end}}
+},
+{{- end}}
+{{- end}}
+
+func getProviderConfigArguments() []*providerConfigArgument {
+	return []*providerConfigArgument{
+		{{- template "providerConfigArguments" .ProviderConfigArguments}}
+	}
+}
+
+// The configuration suggested for the provider, every argument commented out
+func getDefaultConfigTemplate() string {
+	return {{backtick .ProviderConfigTemplate}}
+}
+
+{{- if .MultiClient}}
+// Check that the required arguments are set and that every argument has a value of its type, in the configuration
+// every client is started with
+func validateProviderConfig(config *viper.Viper) *schema.Diagnostics {
+	diagnostics := schema.NewDiagnostics()
+	clientConfigs, err := getClientConfigs(config)
+	if err != nil {
+		return diagnostics.AddErrorMsg("%s", err.Error())
+	}
+	hasClients := config != nil && config.IsSet("clients")
+	for index, clientConfig := range clientConfigs {
+		for _, message := range validateProviderConfigArguments(clientConfig.Config, getProviderConfigArguments(), "") {
+			if hasClients {
+				message = fmt.Sprintf("clients[%d]: %s", index, message)
+			}
+			diagnostics.AddErrorMsg("%s", message)
+		}
+	}
+	return diagnostics
+}
+
+// clientConfig An entry of clients, the client is started with the arguments of the provider overlaid with Config
+type clientConfig struct {
+	AccountID string
+	Region    string
+
+	// The arguments the terraform provider of the client is started with
+	Config map[string]any
+}
+
+// The configuration of every client, one client with the arguments of the provider when clients is not set
+func getClientConfigs(config *viper.Viper) ([]*clientConfig, error) {
+	values := make(map[string]any)
+	if config != nil {
+		values = config.AllSettings()
+	}
+	entries, hasClients := values["clients"]
+	delete(values, "clients")
+	if !hasClients || entries == nil {
+		return []*clientConfig{
+			{Region: providerConfigString(values["region"]), Config: values},
+		}, nil
+	}
+
+	entrySlice, ok := entries.([]any)
+	if !ok {
+		return nil, fmt.Errorf("clients must be a list of mappings")
+	}
+	clientConfigs := make([]*clientConfig, 0, len(entrySlice))
+	for index, entry := range entrySlice {
+		mapping, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("clients[%d] must be a mapping", index)
+		}
+		overlay := make(map[string]any)
+		if mapping["config"] != nil {
+			if overlay, ok = mapping["config"].(map[string]any); !ok {
+				return nil, fmt.Errorf("clients[%d].config must be a mapping", index)
+			}
+		}
+		merged := mergeProviderConfig(values, overlay)
+		region := providerConfigString(mapping["region"])
+		if region == "" {
+			region = providerConfigString(merged["region"])
+		}
+		clientConfigs = append(clientConfigs, &clientConfig{
+			AccountID: providerConfigString(mapping["account_id"]),
+			Region:    region,
+			Config:    merged,
+		})
+	}
+	return clientConfigs, nil
+}
+
+// The arguments overlaid with the ones of a client, a mapping in both is merged key by key
+func mergeProviderConfig(base, overlay map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		baseMapping, baseIsMapping := merged[key].(map[string]any)
+		overlayMapping, overlayIsMapping := value.(map[string]any)
+		if baseIsMapping && overlayIsMapping {
+			merged[key] = mergeProviderConfig(baseMapping, overlayMapping)
+		} else {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+func providerConfigString(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+{{- else}}
+// Check that the required arguments are set and that every argument has a value of its type
+func validateProviderConfig(config *viper.Viper) *schema.Diagnostics {
+	diagnostics := schema.NewDiagnostics()
+	values := make(map[string]any)
+	if config != nil {
+		values = config.AllSettings()
+	}
+	for _, message := range validateProviderConfigArguments(values, getProviderConfigArguments(), "") {
+		diagnostics.AddErrorMsg("%s", message)
+	}
+	return diagnostics
+}
+{{- end}}
+
+func validateProviderConfigArguments(values map[string]any, arguments []*providerConfigArgument, keyPrefix string) []string {
+	messages := make([]string, 0)
+	for _, argument := range arguments {
+		key := keyPrefix + argument.Name
+		value, ok := values[argument.Name]
+		if !ok || value == nil {
+			if argument.Required && !argument.HasDefault {
+				messages = append(messages, fmt.Sprintf("%s is required", key))
+			}
+			continue
+		}
+		messages = append(messages, validateProviderConfigValue(value, argument, key)...)
+	}
+	return messages
+}
+
+func validateProviderConfigValue(value any, argument *providerConfigArgument, key string) []string {
+	if len(argument.Arguments) != 0 {
+		blocks, ok := value.([]any)
+		if !ok {
+			if _, isMapping := value.(map[string]any); !isMapping || argument.MaxItems != 1 {
+				return []string{fmt.Sprintf("%s must be a list of mappings", key)}
+			}
+			return validateProviderConfigArguments(value.(map[string]any), argument.Arguments, key+".")
+		}
+		messages := make([]string, 0)
+		if argument.MaxItems > 0 && len(blocks) > argument.MaxItems {
+			messages = append(messages, fmt.Sprintf("%s must have at most %d item(s)", key, argument.MaxItems))
+		}
+		for index, block := range blocks {
+			mapping, ok := block.(map[string]any)
+			if !ok {
+				messages = append(messages, fmt.Sprintf("%s[%d] must be a mapping", key, index))
+				continue
+			}
+			messages = append(messages, validateProviderConfigArguments(mapping, argument.Arguments, fmt.Sprintf("%s[%d].", key, index))...)
+		}
+		return messages
+	}
+
+	if !isProviderConfigType(value, argument.Type) {
+		return []string{fmt.Sprintf("%s must be a %s", key, argument.Type)}
+	}
+	if argument.ElemType == "" {
+		return nil
+	}
+	messages := make([]string, 0)
+	switch collection := value.(type) {
+	case []any:
+		for index, elem := range collection {
+			if !isProviderConfigType(elem, argument.ElemType) {
+				messages = append(messages, fmt.Sprintf("%s[%d] must be a %s", key, index, argument.ElemType))
+			}
+		}
+	case map[string]any:
+		for name, elem := range collection {
+			if !isProviderConfigType(elem, argument.ElemType) {
+				messages = append(messages, fmt.Sprintf("%s.%s must be a %s", key, name, argument.ElemType))
+			}
+		}
+	}
+	return messages
+}
+
+func isProviderConfigType(value any, valueType string) bool {
+	switch valueType {
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	case "int":
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case float64:
+			return v == math.Trunc(v)
+		}
+		return false
+	case "float":
+		switch value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return true
+		}
+		return false
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "list", "set":
+		_, ok := value.([]any)
+		return ok
+	case "map":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+	return true
+}
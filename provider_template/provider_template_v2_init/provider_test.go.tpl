@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+)
+
+func Test_getTerraformProviderExecuteFileSlice(t *testing.T) {
+	sha256SumRegex := regexp.MustCompile(`^[0-9a-f]{64}$`)
+	providerFileSlice := getTerraformProviderExecuteFileSlice()
+	if len(providerFileSlice) == 0 {
+		t.Fatal("the terraform provider has no execute file")
+	}
+	platforms := make(map[string]int)
+	for index, file := range providerFileSlice {
+		if file.ProviderName == "" || file.ProviderVersion == "" || file.DownloadUrl == "" || file.OS == "" || file.Arch == "" {
+			t.Errorf("execute file %d is incomplete: %+v", index, file)
+		}
+		if !sha256SumRegex.MatchString(file.Sha256Sum) {
+			t.Errorf("execute file %d has no sha256 checksum: %+v", index, file)
+		}
+		if file.ProviderName != providerFileSlice[0].ProviderName || file.ProviderVersion != providerFileSlice[0].ProviderVersion {
+			t.Errorf("execute file %d is not the same provider as execute file 0: %+v", index, file)
+		}
+		platform := file.OS + "/" + file.Arch
+		if first, ok := platforms[platform]; ok {
+			t.Errorf("execute file %d and %d are both for %s", first, index, platform)
+		}
+		platforms[platform] = index
+	}
+}
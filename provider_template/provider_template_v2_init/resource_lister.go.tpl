@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/selefra/selefra-provider-sdk/provider/schema"
+	"github.com/selefra/selefra-provider-sdk/terraform/bridge"
+	"github.com/selefra/selefra-provider-sdk/terraform/selefra_terraform_schema"
+)
+
+// Read a data source of the terraform provider without arguments and request a resource for every id it lists. The
+// ids are the strings of listAttribute, or the idAttribute of its blocks, in which case the argumentNames of a block
+// are requested with the id.
+func listResourceParamsByDataSource(ctx context.Context, clientMeta *schema.ClientMeta, taskClient any, dataSourceName, listAttribute, idAttribute string, argumentNames ...string) ([]*selefra_terraform_schema.ResourceRequestParam, *schema.Diagnostics) {
+	diagnostics := schema.NewDiagnostics()
+
+	terraformBridge := getTaskTerraformBridge(ctx, clientMeta, taskClient)
+	if terraformBridge == nil || terraformBridge.GetProvider() == nil {
+		return nil, diagnostics.AddErrorMsg("terraform provider is not running, can not read data source %s", dataSourceName)
+	}
+	provider := terraformBridge.GetProvider()
+	dataSource, ok := provider.DataSourcesMap().GetOk(dataSourceName)
+	if !ok {
+		return nil, diagnostics.AddErrorMsg("terraform provider has no data source %s", dataSourceName)
+	}
+	diff, err := provider.ReadDataDiff(dataSourceName, provider.NewResourceConfig(map[string]any{}))
+	if err != nil {
+		return nil, diagnostics.AddErrorMsg("read data source %s error: %s", dataSourceName, err.Error())
+	}
+	state, err := provider.ReadDataApply(dataSourceName, diff)
+	if err != nil {
+		return nil, diagnostics.AddErrorMsg("read data source %s error: %s", dataSourceName, err.Error())
+	}
+	object, err := state.Object(dataSource.Schema())
+	if err != nil {
+		return nil, diagnostics.AddErrorMsg("read data source %s error: %s", dataSourceName, err.Error())
+	}
+
+	values, _ := object[listAttribute].([]any)
+	params := make([]*selefra_terraform_schema.ResourceRequestParam, 0, len(values))
+	for _, value := range values {
+		if idAttribute == "" {
+			if id, ok := value.(string); ok && id != "" {
+				params = append(params, selefra_terraform_schema.NewResourceRequestParamWithID(id))
+			}
+			continue
+		}
+		block, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		id := fmt.Sprintf("%v", block[idAttribute])
+		if block[idAttribute] == nil || id == "" {
+			continue
+		}
+		argumentMap := make(map[string]any, len(argumentNames))
+		for _, name := range argumentNames {
+			if block[name] != nil {
+				argumentMap[name] = block[name]
+			}
+		}
+		params = append(params, selefra_terraform_schema.NewResourceRequestParamWithIDAndArgumentMap(id, argumentMap))
+	}
+	return params, diagnostics
+}
+
+// The bridge of the client of the task, the running one if the client restarts its terraform provider
+func getTaskTerraformBridge(ctx context.Context, clientMeta *schema.ClientMeta, taskClient any) *bridge.TerraformBridge {
+	if managedClient, ok := taskClient.(interface {
+		GetTerraformBridge(ctx context.Context, clientMeta *schema.ClientMeta) *bridge.TerraformBridge
+	}); ok {
+		return managedClient.GetTerraformBridge(ctx, clientMeta)
+	}
+	return taskClient.(*Client).TerraformBridge
+}
@@ -0,0 +1,296 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"github.com/selefra/selefra-provider-sdk/provider/schema"
+	"github.com/selefra/selefra-provider-sdk/terraform/bridge"
+	"google.golang.org/grpc/status"
+)
+
+const (
+
+	// How often the terraform provider of a client is probed at most, the probe runs before a task of the client
+	terraformBridgeHealthCheckInterval = 30 * time.Second
+
+	// How long the terraform provider has to answer the probe
+	terraformBridgeHealthCheckTimeout = 10 * time.Second
+
+	// A terraform provider that does not answer is restarted, waiting twice as long before every next attempt
+	terraformBridgeRestartAttempts = 3
+	terraformBridgeRestartBackoff  = time.Second
+
+	// How many of the last lines of the stderr of a terraform provider are kept for the diagnostics
+	terraformBridgeStderrLines = 20
+)
+
+// The levels of TF_LOG bridge.StartProvider knows, any other value logs nothing
+var terraformBridgeLogLevels = map[string]bool{"TRACE": true, "DEBUG": true, "INFO": true, "WARN": true, "ERROR": true}
+
+var (
+	// The terraform providers live no longer than this context, see SetTerraformBridgeContext
+	terraformBridgeContextLock sync.Mutex
+	terraformBridgeContext     = context.Background()
+
+	// hclog.DefaultOutput and TF_LOG are changed while a terraform provider starts, so one starts at a time
+	terraformBridgeStartLock sync.Mutex
+)
+
+// SetTerraformBridgeContext The terraform providers started from now on are killed when the context is done, the
+// generated main.go gives the context that ends when the provider is interrupted or terminated
+func SetTerraformBridgeContext(ctx context.Context) {
+	terraformBridgeContextLock.Lock()
+	defer terraformBridgeContextLock.Unlock()
+	terraformBridgeContext = ctx
+}
+
+func getTerraformBridgeContext() context.Context {
+	terraformBridgeContextLock.Lock()
+	defer terraformBridgeContextLock.Unlock()
+	return terraformBridgeContext
+}
+
+// ShutdownTerraformBridges Wait for the processes of the terraform providers to exit, the ones still running are
+// killed. The generated main.go calls it once the context of SetTerraformBridgeContext is done.
+func ShutdownTerraformBridges() {
+	plugin.CleanupClients()
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// terraformBridgeSet The terraform providers the clients of one InitClient call started, the next call stops them and
+// leaves the ones of any other set running
+type terraformBridgeSet struct {
+	lock    sync.Mutex
+	bridges []*managedTerraformBridge
+}
+
+func newTerraformBridgeSet() *terraformBridgeSet {
+	return &terraformBridgeSet{}
+}
+
+// Start the terraform provider with the configuration of a client and add it to the set
+func (x *terraformBridgeSet) Start(providerExecFilePath string, providerConfig map[string]any) (*managedTerraformBridge, error) {
+	managedBridge := &managedTerraformBridge{
+		providerExecFilePath: providerExecFilePath,
+		providerConfig:       providerConfig,
+		stderr:               &stderrTail{maxLines: terraformBridgeStderrLines},
+	}
+	if err := managedBridge.start(); err != nil {
+		return nil, err
+	}
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	x.bridges = append(x.bridges, managedBridge)
+	return managedBridge, nil
+}
+
+// Shutdown Stop the terraform providers of the set, it is empty afterwards
+func (x *terraformBridgeSet) Shutdown() {
+	x.lock.Lock()
+	bridges := x.bridges
+	x.bridges = nil
+	x.lock.Unlock()
+	for _, managedBridge := range bridges {
+		managedBridge.Shutdown()
+	}
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// managedTerraformBridge The terraform provider of a client, it is probed before the tasks of the client and restarted
+// when it crashed
+type managedTerraformBridge struct {
+	providerExecFilePath string
+	providerConfig       map[string]any
+
+	// The last lines the process wrote to stderr, a restarted one keeps writing to it
+	stderr *stderrTail
+
+	lock   sync.Mutex
+	bridge *bridge.TerraformBridge
+
+	// Kills the process of the terraform provider
+	cancel context.CancelFunc
+
+	// Set by Shutdown, a terraform provider that was stopped is not restarted
+	stopped bool
+
+	lastHealthCheckTime time.Time
+}
+
+// The caller holds the lock or is the only one that knows the bridge
+func (x *managedTerraformBridge) start() error {
+	// The context of InitClient ends with the request, the process lives as long as the provider unless it is stopped
+	ctx, cancel := context.WithCancel(getTerraformBridgeContext())
+	terraformBridge := bridge.NewTerraformBridge(x.providerExecFilePath)
+	if err := startTerraformBridge(ctx, terraformBridge, x.providerConfig, x.stderr); err != nil {
+		cancel()
+		return x.stderrError(fmt.Errorf("start terraform provider %s error: %s", x.providerExecFilePath, err.Error()))
+	}
+	x.bridge = terraformBridge
+	x.cancel = cancel
+	x.lastHealthCheckTime = time.Now()
+	return nil
+}
+
+// bridge.StartProvider logs the stderr of the process to hclog.DefaultOutput, and only when TF_LOG is set. Its logger
+// takes the output when it is made as the process starts, so only for that moment DefaultOutput is the stderr of this
+// provider, and TF_LOG is DEBUG if it is not set so that a panic, which is not a log line, is kept too. The logs asked
+// for with TF_LOG still reach stderr.
+func startTerraformBridge(ctx context.Context, terraformBridge *bridge.TerraformBridge, providerConfig map[string]any, stderr io.Writer) error {
+	terraformBridgeStartLock.Lock()
+	defer terraformBridgeStartLock.Unlock()
+
+	defaultOutput := hclog.DefaultOutput
+	defer func() {
+		hclog.DefaultOutput = defaultOutput
+	}()
+	if tfLog, ok := os.LookupEnv("TF_LOG"); ok && terraformBridgeLogLevels[tfLog] {
+		hclog.DefaultOutput = io.MultiWriter(defaultOutput, stderr)
+	} else {
+		hclog.DefaultOutput = stderr
+		_ = os.Setenv("TF_LOG", "DEBUG")
+		defer func() {
+			if ok {
+				_ = os.Setenv("TF_LOG", tfLog)
+			} else {
+				_ = os.Unsetenv("TF_LOG")
+			}
+		}()
+	}
+	return terraformBridge.StartBridge(ctx, providerConfig)
+}
+
+// The caller holds the lock
+func (x *managedTerraformBridge) stop() {
+	if x.bridge != nil && x.bridge.GetProvider() != nil {
+		_ = x.bridge.Shutdown()
+	}
+	if x.cancel != nil {
+		x.cancel()
+		x.cancel = nil
+	}
+}
+
+// GetBridge The bridge to run a task with, the terraform provider is probed first when it was not for a while and
+// restarted if it does not answer. A bridge that could not be restarted is returned as it is, the task fails with it.
+func (x *managedTerraformBridge) GetBridge(ctx context.Context, clientMeta *schema.ClientMeta) *bridge.TerraformBridge {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+
+	// A terraform provider that was stopped, or whose provider is exiting, is not restarted
+	if x.stopped || getTerraformBridgeContext().Err() != nil {
+		return x.bridge
+	}
+	if time.Since(x.lastHealthCheckTime) < terraformBridgeHealthCheckInterval {
+		return x.bridge
+	}
+	err := x.healthCheck(ctx)
+	if err == nil {
+		x.lastHealthCheckTime = time.Now()
+		return x.bridge
+	}
+	clientMeta.ErrorF("%s", x.stderrError(fmt.Errorf("terraform provider %s does not answer, restart it: %s", x.providerExecFilePath, err.Error())).Error())
+
+	x.stop()
+	backoff := terraformBridgeRestartBackoff
+	for attempt := 1; attempt <= terraformBridgeRestartAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return x.bridge
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if err := x.start(); err != nil {
+			clientMeta.ErrorF("restart terraform provider, attempt %d of %d: %s", attempt, terraformBridgeRestartAttempts, err.Error())
+			continue
+		}
+		clientMeta.InfoF("terraform provider %s restarted", x.providerExecFilePath)
+		return x.bridge
+	}
+	return x.bridge
+}
+
+// Ask the terraform provider to check the configuration it was started with, any answer means it is alive, only an
+// error of the connection does not
+func (x *managedTerraformBridge) healthCheck(ctx context.Context) error {
+	if x.bridge == nil || x.bridge.GetProvider() == nil {
+		return fmt.Errorf("terraform provider is not running")
+	}
+	provider := x.bridge.GetProvider()
+	resultChannel := make(chan error, 1)
+	go func() {
+		_, errs := provider.Validate(provider.NewResourceConfig(x.providerConfig))
+		for _, err := range errs {
+			if _, isRPCError := status.FromError(err); isRPCError {
+				resultChannel <- err
+				return
+			}
+		}
+		resultChannel <- nil
+	}()
+	select {
+	case err := <-resultChannel:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(terraformBridgeHealthCheckTimeout):
+		return fmt.Errorf("no answer in %s", terraformBridgeHealthCheckTimeout)
+	}
+}
+
+// Shutdown Stop the terraform provider of the client
+func (x *managedTerraformBridge) Shutdown() {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	x.stopped = true
+	x.stop()
+}
+
+// ------------------------------------------------- --------------------------------------------------------------------
+
+// stderrTail The last lines written to it
+type stderrTail struct {
+	lock     sync.Mutex
+	maxLines int
+	lines    []string
+	partial  string
+}
+
+func (x *stderrTail) Write(p []byte) (int, error) {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	lines := strings.Split(x.partial+string(p), "\n")
+	x.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		x.lines = append(x.lines, line)
+	}
+	if len(x.lines) > x.maxLines {
+		x.lines = x.lines[len(x.lines)-x.maxLines:]
+	}
+	return len(p), nil
+}
+
+func (x *stderrTail) String() string {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	return strings.Join(x.lines, "\n")
+}
+
+// The error with the last lines the terraform provider wrote to stderr
+func (x *managedTerraformBridge) stderrError(err error) error {
+	stderr := x.stderr.String()
+	if stderr == "" {
+		return err
+	}
+	return fmt.Errorf("%w, the last lines of the stderr of the terraform provider:\n%s", err, stderr)
+}